@@ -41,6 +41,8 @@ func Start(port string) {
 	r.POST("/api/auth/signin", SigninHandler)
 	r.POST("/api/auth/signup", SignupHandler)
 	r.POST("/api/match", MatchHandler)
+	r.GET("/api/match/:id/journal", JournalHandler)
+	r.GET("/api/match/:id/result", ResultHandler)
 	r.GET("/api/cards", CardsHandler)
 	r.GET("/api/decks", GetDecksHandler)
 	r.POST("/api/decks", CreateDeckHandler)
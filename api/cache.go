@@ -10,10 +10,12 @@ import (
 
 // CardInfo struct is used for the card database api
 type CardInfo struct {
-	UID          int    `json:"uid"`
-	Name         string `json:"name"`
-	Civilization string `json:"civilization"`
-	Set          string `json:"set"`
+	UID          int      `json:"uid"`
+	Name         string   `json:"name"`
+	Civilization string   `json:"civilization"`
+	Set          string   `json:"set"`
+	Text         string   `json:"text"`
+	Keywords     []string `json:"keywords"`
 }
 
 // Register holds all the card info
@@ -31,6 +33,8 @@ func CreateCardCache() {
 				Name:         card.Name(),
 				Civilization: string(card.Civ()),
 				Set:          setID,
+				Text:         card.Text(),
+				Keywords:     card.Keywords(),
 			})
 		}
 	}
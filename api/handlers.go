@@ -3,10 +3,12 @@ package api
 import (
 	"context"
 	"net/http"
+	"strconv"
 	"time"
 
 	"github.com/jyotiskaghosh/ganjifa/db"
 	"github.com/jyotiskaghosh/ganjifa/game"
+	gameapi "github.com/jyotiskaghosh/ganjifa/game-api/match"
 	"github.com/jyotiskaghosh/ganjifa/game/match"
 	"github.com/jyotiskaghosh/ganjifa/server"
 
@@ -136,6 +138,21 @@ func SignupHandler(c *gin.Context) {
 type matchReqBody struct {
 	Name       string `json:"name" binding:"required,min=3,max=100"`
 	Visibility string `json:"visibility" binding:"required"`
+	Opponent   string `json:"opponent"`
+	Difficulty string `json:"difficulty"`
+
+	// TimeControl is given in seconds
+	TimeControl struct {
+		Turn      int `json:"turn"`
+		Clock     int `json:"clock"`
+		Increment int `json:"increment"`
+		Prompt    int `json:"prompt"`
+	} `json:"timeControl"`
+
+	Mulligan gameapi.Mulligan `json:"mulligan"`
+
+	// BestOf is how many games the match is played over, a single game if it is left out
+	BestOf int `json:"bestOf"`
 }
 
 // MatchHandler handles creation of new mathes
@@ -157,11 +174,97 @@ func MatchHandler(c *gin.Context) {
 		visible = false
 	}
 
-	m := match.New(reqBody.Name, user.Username, visible)
+	tc := gameapi.TimeControl{
+		Turn:      time.Duration(reqBody.TimeControl.Turn) * time.Second,
+		Clock:     time.Duration(reqBody.TimeControl.Clock) * time.Second,
+		Increment: time.Duration(reqBody.TimeControl.Increment) * time.Second,
+		Prompt:    time.Duration(reqBody.TimeControl.Prompt) * time.Second,
+	}
+
+	settings := match.Settings{
+		TimeControl: tc,
+		Mulligan:    reqBody.Mulligan,
+		BestOf:      reqBody.BestOf,
+	}
+
+	if err := settings.Validate(); err != nil {
+		logrus.Debug(err)
+		c.Status(400)
+		return
+	}
+
+	var m *match.Match
+
+	if reqBody.Opponent == "bot" {
+		m, err = match.NewAgainstBot(reqBody.Name, user.Username, reqBody.Difficulty, settings)
+	} else {
+		m, err = match.New(reqBody.Name, user.Username, visible, settings)
+	}
+
+	if err != nil {
+		logrus.Error(err)
+		c.Status(500)
+		return
+	}
 
 	c.JSON(200, m.Info())
 }
 
+// JournalHandler returns the journal of a game of a finished match to one of its players,
+// the game is given by the game query parameter and defaults to the first
+func JournalHandler(c *gin.Context) {
+	user, err := db.GetUserForToken(c.GetHeader("Authorization"))
+	if err != nil {
+		c.Status(401)
+		return
+	}
+
+	game, err := strconv.Atoi(c.DefaultQuery("game", "1"))
+	if err != nil {
+		c.Status(400)
+		return
+	}
+
+	journal, err := match.FindJournal(c.Param("id"), game)
+	if err != nil {
+		c.Status(404)
+		return
+	}
+
+	for _, player := range journal.Players {
+		if player == user.Username {
+			c.JSON(200, journal)
+			return
+		}
+	}
+
+	c.Status(403)
+}
+
+// ResultHandler returns the result of a finished series to one of its players
+func ResultHandler(c *gin.Context) {
+	user, err := db.GetUserForToken(c.GetHeader("Authorization"))
+	if err != nil {
+		c.Status(401)
+		return
+	}
+
+	series, err := match.FindResult(c.Param("id"))
+	if err != nil {
+		c.Status(404)
+		return
+	}
+
+	for _, player := range series.Players {
+		if player == user.Username {
+			c.JSON(200, series)
+			return
+		}
+	}
+
+	c.Status(403)
+}
+
 var upgrader = websocket.Upgrader{
 	ReadBufferSize:  1024,
 	WriteBufferSize: 1024,
@@ -247,6 +350,8 @@ type createDeckBody struct {
 	Cards  []int  `json:"cards" binding:"required"`
 	UID    string `json:"uid"`
 	Public bool   `json:"public"`
+
+	Sideboard []int `json:"sideboard"`
 }
 
 // CreateDeckHandler handles creating/editing decks
@@ -268,7 +373,16 @@ func CreateDeckHandler(c *gin.Context) {
 		return
 	}
 
-	for _, cuid := range reqBody.Cards {
+	if len(reqBody.Sideboard) > 15 {
+		c.Status(400)
+		return
+	}
+
+	if reqBody.Sideboard == nil {
+		reqBody.Sideboard = make([]int, 0)
+	}
+
+	for _, cuid := range append(append(make([]int, 0), reqBody.Cards...), reqBody.Sideboard...) {
 		if !CacheHas(cuid) {
 			c.Status(400)
 			return
@@ -300,6 +414,8 @@ func CreateDeckHandler(c *gin.Context) {
 			Public:   reqBody.Public,
 			Standard: false,
 			Cards:    reqBody.Cards,
+
+			Sideboard: reqBody.Sideboard,
 		}
 
 		_, err = collection.InsertOne(context.TODO(), deck)
@@ -314,7 +430,7 @@ func CreateDeckHandler(c *gin.Context) {
 		_, err := collection.UpdateOne(
 			context.TODO(),
 			bson.M{"uid": reqBody.UID, "owner": user.UID},
-			bson.M{"$set": bson.M{"name": reqBody.Name, "public": reqBody.Public, "cards": reqBody.Cards}},
+			bson.M{"$set": bson.M{"name": reqBody.Name, "public": reqBody.Public, "cards": reqBody.Cards, "sideboard": reqBody.Sideboard}},
 		)
 
 		if err != nil {
@@ -1,13 +1,14 @@
 package main
 
 import (
-	"math/rand"
 	"os"
-	"time"
+	"os/signal"
+	"syscall"
 
 	"github.com/jyotiskaghosh/ganjifa/api"
 	"github.com/jyotiskaghosh/ganjifa/db"
 	"github.com/jyotiskaghosh/ganjifa/game"
+	gamematch "github.com/jyotiskaghosh/ganjifa/game/match"
 
 	"github.com/jyotiskaghosh/ganjifa/game-api/cards"
 	"github.com/jyotiskaghosh/ganjifa/game-api/match"
@@ -19,10 +20,14 @@ func main() {
 	logrus.SetFormatter(&logrus.JSONFormatter{})
 	logrus.SetLevel(logrus.DebugLevel)
 
-	rand.Seed(time.Now().UnixNano())
-
 	logrus.Info("Starting..")
 
+	if dir := os.Getenv("cards_dir"); dir != "" {
+		if err := cards.LoadSets(dir); err != nil {
+			logrus.Fatalf("Couldn't load cards: %s", err)
+		}
+	}
+
 	for _, set := range cards.Sets {
 		for uid, ctor := range *set {
 			match.AddCard(uid, ctor)
@@ -35,5 +40,20 @@ func main() {
 
 	db.Connect(os.Getenv("mongo_uri"), os.Getenv("mongo_name"))
 
+	gamematch.RestoreSnapshots()
+
+	go func() {
+		signals := make(chan os.Signal, 1)
+		signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+
+		<-signals
+
+		logrus.Info("Shutting down..")
+
+		gamematch.SaveSnapshots()
+
+		os.Exit(0)
+	}()
+
 	api.Start(os.Getenv("port"))
 }
@@ -26,4 +26,7 @@ type Deck struct {
 	Public   bool   `json:"public"`
 	Standard bool   `json:"standard"`
 	Cards    []int  `json:"cards"`
+
+	// Sideboard holds the cards that can be swapped into the deck between the games of a series
+	Sideboard []int `json:"sideboard"`
 }
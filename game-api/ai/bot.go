@@ -0,0 +1,204 @@
+package ai
+
+import (
+	"encoding/json"
+	"errors"
+
+	"github.com/jyotiskaghosh/ganjifa/game-api/match"
+
+	"github.com/sirupsen/logrus"
+)
+
+// maxActions caps the number of inputs a bot sends in a single turn
+const maxActions = 100
+
+// Action is a top level input a bot can send to a match
+type Action struct {
+	Header string `json:"header"`
+	ID     string `json:"id,omitempty"`
+
+	// Target is the creature to select when attacking a creature
+	Target string `json:"-"`
+}
+
+// Strategy decides what a bot does
+type Strategy interface {
+	// NextAction returns the next input to send during the bot's turn
+	NextAction(b *Bot) Action
+	// Decide answers the prompts of the bot
+	Decide(b *Bot, prompt match.Prompt) match.Decision
+}
+
+// Bot is a server side player that plays a match through the same inputs as a human
+type Bot struct {
+	name     string
+	match    *match.Match
+	player   *match.Player
+	strategy Strategy
+
+	target string
+
+	poke chan bool
+	quit chan bool
+}
+
+// New returns a new bot for the given match
+func New(m *match.Match, name string, strategy Strategy) *Bot {
+	return &Bot{
+		name:     name,
+		match:    m,
+		strategy: strategy,
+		poke:     make(chan bool, 1),
+		quit:     make(chan bool),
+	}
+}
+
+// Name returns the name of the bot
+func (b *Bot) Name() string {
+	return b.name
+}
+
+// Match returns the match the bot is playing
+func (b *Bot) Match() *match.Match {
+	return b.match
+}
+
+// Player returns the bot's player, or nil if it has not joined yet
+func (b *Bot) Player() *match.Player {
+	return b.player
+}
+
+// Join adds the bot to its match and chooses the given deck
+func (b *Bot) Join(deck []int) error {
+	if err := b.match.AddPlayer(b.name, b); err != nil {
+		return err
+	}
+
+	p, err := b.match.PlayerForWriter(b)
+	if err != nil {
+		return err
+	}
+
+	b.player = p
+	b.player.SetDecider(b)
+
+	data, err := json.Marshal(struct {
+		Header string `json:"header"`
+		match.CreateDeck
+	}{"choose_deck", match.CreateDeck{Cards: deck}})
+	if err != nil {
+		return err
+	}
+
+	b.send(data)
+
+	if !b.player.Ready() {
+		return errors.New("the bot's deck is invalid")
+	}
+
+	return nil
+}
+
+// Resume takes the bot's seat again in a match restored from a snapshot
+func (b *Bot) Resume() error {
+	for _, p := range b.match.Players() {
+		if p.Name() != b.name {
+			continue
+		}
+
+		if err := b.match.Rebind(p, b); err != nil {
+			return err
+		}
+
+		b.player = p
+		b.player.SetDecider(b)
+
+		return nil
+	}
+
+	return errors.New("the bot has no seat in this match")
+}
+
+// Write is called with every message the match sends to the bot, which has no use for them
+// since it reads the match directly
+func (b *Bot) Write(msg interface{}) {}
+
+// Decide answers the bot's prompts
+func (b *Bot) Decide(p *match.Player, prompt match.Prompt) match.Decision {
+	if b.target != "" && match.AssertCardsIn(prompt.Cards, b.target) {
+		target := b.target
+		b.target = ""
+
+		return match.Decision{Cards: []string{target}}
+	}
+
+	return b.strategy.Decide(b, prompt)
+}
+
+// Poke tells the bot that the match has processed an input, so it might be its turn now
+func (b *Bot) Poke() {
+	select {
+	case b.poke <- true:
+	default:
+	}
+}
+
+// Run plays the bot's turns whenever it is poked, until it is stopped
+func (b *Bot) Run() {
+	defer func() {
+		if r := recover(); r != nil {
+			logrus.Warnf("Recovered from bot. %v", r)
+		}
+	}()
+
+	for {
+		select {
+		case <-b.quit:
+			return
+		case <-b.poke:
+			b.takeTurn()
+		}
+	}
+}
+
+// Stop stops the bot
+func (b *Bot) Stop() {
+	close(b.quit)
+}
+
+// takeTurn sends actions chosen by the strategy until the bot's turn is over
+func (b *Bot) takeTurn() {
+	for i := 0; i < maxActions; i++ {
+		if !b.match.Started() || b.match.Ended() || !b.player.IsPlayerTurn() {
+			return
+		}
+
+		action := b.strategy.NextAction(b)
+		b.act(action)
+
+		if action.Header == "end_turn" {
+			return
+		}
+	}
+
+	b.send([]byte(`{"header":"end_turn"}`))
+}
+
+// act sends a single action to the match
+func (b *Bot) act(action Action) {
+	b.target = action.Target
+	defer func() { b.target = "" }()
+
+	data, err := json.Marshal(action)
+	if err != nil {
+		logrus.Debug(err)
+		return
+	}
+
+	b.send(data)
+}
+
+// send hands an input to the match as if it came from the bot's client
+func (b *Bot) send(data []byte) {
+	b.match.Input(b.player, data)
+}
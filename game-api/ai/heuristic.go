@@ -0,0 +1,297 @@
+package ai
+
+import (
+	"sort"
+
+	"github.com/jyotiskaghosh/ganjifa/game-api/family"
+	"github.com/jyotiskaghosh/ganjifa/game-api/fx"
+	"github.com/jyotiskaghosh/ganjifa/game-api/match"
+)
+
+// holdBackLife is the life below which the bot keeps its best blocker home
+const holdBackLife = 8
+
+// maxTraps is the number of set down cards the bot is happy to have
+const maxTraps = 3
+
+// Heuristic is a rule based strategy that plays out its hand, makes the trades that
+// are in its favour and blocks when it matters
+type Heuristic struct {
+	turn      uint8
+	attempted map[string]bool
+	tried     map[string]bool
+}
+
+// NewHeuristic returns a new heuristic strategy
+func NewHeuristic() *Heuristic {
+	return &Heuristic{
+		attempted: make(map[string]bool),
+		tried:     make(map[string]bool),
+	}
+}
+
+// NextAction returns the next input to send during the bot's turn
+func (h *Heuristic) NextAction(b *Bot) Action {
+	me := b.Player()
+	opponent := b.Match().Opponent(me)
+	ctx := match.NewContext(b.Match(), nil)
+
+	if h.turn != me.Turn() {
+		h.turn = me.Turn()
+		h.attempted = make(map[string]bool)
+		h.tried = make(map[string]bool)
+	}
+
+	legal := b.Match().LegalActions(me)
+
+	playable := make([]*match.Card, 0)
+	for _, id := range legal.Play {
+		if c, err := match.GetCard(id, me.CollectCards(match.HAND)); err == nil {
+			playable = append(playable, c)
+		}
+	}
+
+	for _, c := range h.byPriority(playable, ctx) {
+		if h.attempted[c.ID()] || !h.worthPlaying(b, c) {
+			continue
+		}
+
+		h.attempted[c.ID()] = true
+
+		return Action{Header: "play_card", ID: c.ID()}
+	}
+
+	if len(me.CollectCards(match.TRAPZONE)) < maxTraps {
+		for _, id := range legal.Set {
+			c, err := match.GetCard(id, me.CollectCards(match.HAND))
+			if err == nil && !h.attempted[id] && c.HasHandler(fx.Ambush, ctx) {
+				h.attempted[id] = true
+
+				return Action{Header: "set_card", ID: id}
+			}
+		}
+	}
+
+	blocker := h.bestBlocker(me, ctx)
+	holdBack := me.Life() <= holdBackLife && len(opponent.CollectCards(match.BATTLEZONE)) > 0
+
+	for _, attack := range legal.Attacks {
+		c, err := match.GetCard(attack.ID, me.CollectCards(match.BATTLEZONE))
+		if err != nil || h.attempted[attack.ID] {
+			continue
+		}
+
+		h.attempted[attack.ID] = true
+
+		targets := make([]*match.Card, 0)
+		for _, id := range attack.Targets {
+			if target, err := match.GetCard(id, opponent.CollectCards(match.BATTLEZONE)); err == nil {
+				targets = append(targets, target)
+			}
+		}
+
+		if target := h.bestTarget(c, targets, ctx); target != nil {
+			return Action{Header: "attack_creature", ID: c.ID(), Target: target.ID()}
+		}
+
+		if !attack.Player || (holdBack && c == blocker) {
+			continue
+		}
+
+		return Action{Header: "attack_player", ID: c.ID()}
+	}
+
+	return Action{Header: "end_turn"}
+}
+
+// Decide answers the bot's prompts
+func (h *Heuristic) Decide(b *Bot, prompt match.Prompt) match.Decision {
+	ctx := match.NewContext(b.Match(), nil)
+
+	// Set down cards are kept for attacks rather than spent on the stack
+	if prompt.Response {
+		return match.Decision{Cancel: true}
+	}
+
+	if b.Match().Phase() == match.MulliganPhase {
+		return h.mulligan(b, prompt, ctx)
+	}
+
+	if prompt.Source != nil && prompt.Source.Player() != b.Player() {
+		return h.defend(b, prompt, ctx)
+	}
+
+	n := prompt.Min
+	if n < 1 {
+		n = 1
+	}
+
+	if n > prompt.Max {
+		n = prompt.Max
+	}
+
+	mine := make([]*match.Card, 0)
+	theirs := make([]*match.Card, 0)
+
+	for _, c := range prompt.Cards {
+		if c.Player() == b.Player() {
+			mine = append(mine, c)
+		} else {
+			theirs = append(theirs, c)
+		}
+	}
+
+	// Prefer the opponent's best cards, most selections are meant to hurt them
+	cards := append(h.byStrength(theirs, ctx), h.byStrength(mine, ctx)...)
+
+	decision := match.Decision{Cards: make([]string, 0)}
+
+	for i := 0; i < n && i < len(cards); i++ {
+		decision.Cards = append(decision.Cards, cards[i].ID())
+	}
+
+	return decision
+}
+
+// defend decides how to respond to an attack. Every card is offered against an attacker only once,
+// in case the attacker can't be trapped or blocked by it
+func (h *Heuristic) defend(b *Bot, prompt match.Prompt, ctx *match.Context) match.Decision {
+	attack := int(prompt.Source.GetAttack(ctx))
+
+	respond := func(c *match.Card) match.Decision {
+		h.tried[prompt.Source.ID()+c.ID()] = true
+		return match.Decision{Cards: []string{c.ID()}}
+	}
+
+	for _, c := range prompt.Cards {
+		if c.Zone() == match.TRAPZONE && !h.tried[prompt.Source.ID()+c.ID()] &&
+			c.HasHandler(fx.Ambush, ctx) && c.GetAttack(ctx) > prompt.Source.GetDefence(ctx) {
+			return respond(c)
+		}
+	}
+
+	var chump *match.Card
+
+	for _, c := range h.byStrength(prompt.Cards, ctx) {
+		if c.Zone() != match.BATTLEZONE || h.tried[prompt.Source.ID()+c.ID()] {
+			continue
+		}
+
+		// A blocker that survives costs nothing
+		if int(c.GetDefence(ctx)) >= attack {
+			return respond(c)
+		}
+
+		chump = c
+	}
+
+	// Throw the weakest creature in front of attacks that would take half our life or more
+	if chump != nil && attack*2 >= b.Player().Life() {
+		return respond(chump)
+	}
+
+	return match.Decision{Cancel: true}
+}
+
+// mulligan keeps a hand that has a creature to summon on the first turn. Otherwise the cards
+// that can't be played yet are put back, unless the penalty would cost half the hand
+func (h *Heuristic) mulligan(b *Bot, prompt match.Prompt, ctx *match.Context) match.Decision {
+	decision := match.Decision{Cards: make([]string, 0)}
+
+	for _, c := range prompt.Cards {
+		if c.Family() != family.Spell && c.Family() != family.Equipment && c.GetRank(ctx) == 0 {
+			return match.Decision{Cancel: true}
+		}
+	}
+
+	if b.Match().Mulligan().Penalty >= len(prompt.Cards)/2 {
+		return match.Decision{Cancel: true}
+	}
+
+	for _, c := range prompt.Cards {
+		if c.GetRank(ctx) > 0 {
+			decision.Cards = append(decision.Cards, c.ID())
+		}
+	}
+
+	return decision
+}
+
+// worthPlaying returns true if playing a legal card would do something useful
+func (h *Heuristic) worthPlaying(b *Bot, c *match.Card) bool {
+	return c.Family() != family.Spell || len(b.Match().Opponent(b.Player()).CollectCards(match.BATTLEZONE)) > 0
+}
+
+// bestTarget returns the strongest of the targets the attacker can destroy
+func (h *Heuristic) bestTarget(attacker *match.Card, targets []*match.Card, ctx *match.Context) *match.Card {
+	for _, c := range h.byStrength(targets, ctx) {
+		if attacker.GetAttack(ctx) > c.GetDefence(ctx) {
+			return c
+		}
+	}
+
+	return nil
+}
+
+// bestBlocker returns the untapped creature with the highest defence
+func (h *Heuristic) bestBlocker(p *match.Player, ctx *match.Context) *match.Card {
+	var best *match.Card
+
+	for _, c := range p.CollectCards(match.BATTLEZONE) {
+		if !c.Tapped && (best == nil || c.GetDefence(ctx) > best.GetDefence(ctx)) {
+			best = c
+		}
+	}
+
+	return best
+}
+
+// byPriority orders a hand the way the bot wants to play it,
+// evolutions first, then equipments, rank 0 creatures and spells last
+func (h *Heuristic) byPriority(cards []*match.Card, ctx *match.Context) []*match.Card {
+	priority := func(c *match.Card) int {
+		switch {
+		case c.Family() == family.Spell:
+			return 3
+		case c.Family() == family.Equipment:
+			return 1
+		case c.GetRank(ctx) > 0:
+			return 0
+		default:
+			return 2
+		}
+	}
+
+	result := append(make([]*match.Card, 0), cards...)
+
+	sort.SliceStable(result, func(i, j int) bool {
+		if priority(result[i]) != priority(result[j]) {
+			return priority(result[i]) < priority(result[j])
+		}
+
+		return result[i].GetRank(ctx) > result[j].GetRank(ctx)
+	})
+
+	return result
+}
+
+// byStrength orders cards by rank, attack and defence, strongest first
+func (h *Heuristic) byStrength(cards []*match.Card, ctx *match.Context) []*match.Card {
+	result := append(make([]*match.Card, 0), cards...)
+
+	sort.SliceStable(result, func(i, j int) bool {
+		a, b := result[i], result[j]
+
+		if a.GetRank(ctx) != b.GetRank(ctx) {
+			return a.GetRank(ctx) > b.GetRank(ctx)
+		}
+
+		if a.GetAttack(ctx) != b.GetAttack(ctx) {
+			return a.GetAttack(ctx) > b.GetAttack(ctx)
+		}
+
+		return a.GetDefence(ctx) > b.GetDefence(ctx)
+	})
+
+	return result
+}
@@ -0,0 +1,334 @@
+package ai
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"time"
+
+	"github.com/jyotiskaghosh/ganjifa/game-api/fx"
+	"github.com/jyotiskaghosh/ganjifa/game-api/match"
+)
+
+// exploration is the UCB1 exploration constant
+const exploration = 0.7
+
+// MCTS is a strategy that looks ahead with a Monte-Carlo tree search. Every iteration plays a clone
+// of the match, whose hidden cards are sampled, down the search tree and on with the heuristic
+// strategy, and the action that did best is taken. Prompts are answered by the heuristic strategy
+type MCTS struct {
+	// Iterations is the maximum number of playouts per action
+	Iterations int
+	// Duration is the maximum time spent thinking per action
+	Duration time.Duration
+	// Depth is the number of actions a playout looks ahead before the outcome is estimated
+	Depth int
+
+	heuristic *Heuristic
+	rand      *rand.Rand
+
+	turn  uint8
+	tried map[string]bool
+}
+
+// NewMCTS returns a new tree search strategy
+func NewMCTS(iterations int, duration time.Duration, depth int) *MCTS {
+	return &MCTS{
+		Iterations: iterations,
+		Duration:   duration,
+		Depth:      depth,
+		heuristic:  NewHeuristic(),
+		rand:       rand.New(rand.NewSource(time.Now().UnixNano())),
+		tried:      make(map[string]bool),
+	}
+}
+
+// node is a node of the search tree, reached by taking action
+type node struct {
+	parent   *node
+	action   Action
+	player   int
+	children map[string]*node
+
+	visits float64
+	avail  float64
+	reward float64
+}
+
+// newNode returns a new node
+func newNode(parent *node, action Action, player int) *node {
+	return &node{
+		parent:   parent,
+		action:   action,
+		player:   player,
+		children: make(map[string]*node),
+	}
+}
+
+// key identifies an action within the search tree
+func (a Action) key() string {
+	return fmt.Sprintf("%s:%s:%s", a.Header, a.ID, a.Target)
+}
+
+// NextAction returns the action that did best in the search
+func (s *MCTS) NextAction(b *Bot) Action {
+	me := b.Player()
+
+	if s.turn != me.Turn() {
+		s.turn = me.Turn()
+		s.tried = make(map[string]bool)
+	}
+
+	actions := make([]Action, 0)
+
+	for _, a := range candidates(b.Match(), me) {
+		if !s.tried[a.key()] && (a.Header == "end_turn" || s.changes(b, a)) {
+			actions = append(actions, a)
+		}
+	}
+
+	if len(actions) == 1 {
+		return actions[0]
+	}
+
+	root := newNode(nil, Action{}, 0)
+	for _, a := range actions {
+		root.children[a.key()] = newNode(root, a, playerIndex(b.Match(), me))
+	}
+
+	deadline := time.Now().Add(s.Duration)
+
+	for i := 0; i < s.Iterations && time.Now().Before(deadline); i++ {
+		s.iterate(root, actions, b)
+	}
+
+	var best *node
+
+	for _, a := range actions {
+		if child := root.children[a.key()]; best == nil || child.visits > best.visits {
+			best = child
+		}
+	}
+
+	s.tried[best.action.key()] = true
+
+	return best.action
+}
+
+// Decide answers the bot's prompts
+func (s *MCTS) Decide(b *Bot, prompt match.Prompt) match.Decision {
+	return s.heuristic.Decide(b, prompt)
+}
+
+// changes returns true if taking the action on a copy of the match, as far as the bot can see it,
+// changes anything
+func (s *MCTS) changes(b *Bot, a Action) bool {
+	me := playerIndex(b.Match(), b.Player())
+
+	clone := b.Match().Clone()
+	clone.Determinize(clone.Players()[me], s.rand.Int63())
+
+	before := fingerprint(clone)
+
+	bots := playouts(clone)
+	bots[playerIndex(clone, clone.CurrentPlayer())].act(a)
+
+	return fingerprint(clone) != before
+}
+
+// iterate runs a single iteration of the search, selecting and expanding a path down the tree,
+// playing it out with the heuristic strategy and backing up the outcome.
+// The root is limited to the given actions, which are known to be worth taking in the real match
+func (s *MCTS) iterate(root *node, actions []Action, b *Bot) {
+	me := playerIndex(b.Match(), b.Player())
+
+	clone := b.Match().Clone()
+	clone.Determinize(clone.Players()[me], s.rand.Int63())
+
+	bots := playouts(clone)
+	n := root
+	depth := 0
+
+	for !clone.Ended() && depth < s.Depth {
+		turn := playerIndex(clone, clone.CurrentPlayer())
+		if n != root {
+			actions = candidates(clone, clone.Players()[turn])
+		}
+
+		untried := make([]Action, 0)
+
+		for _, a := range actions {
+			if child, ok := n.children[a.key()]; ok {
+				child.avail++
+			} else {
+				untried = append(untried, a)
+			}
+		}
+
+		var next *node
+
+		if len(untried) > 0 {
+			a := untried[s.rand.Intn(len(untried))]
+			next = newNode(n, a, turn)
+			next.avail++
+			n.children[a.key()] = next
+		} else {
+			next = n.pick(actions)
+		}
+
+		bots[turn].act(next.action)
+		depth++
+
+		n = next
+
+		if len(untried) > 0 {
+			break
+		}
+	}
+
+	for !clone.Ended() && depth < s.Depth {
+		bot := bots[playerIndex(clone, clone.CurrentPlayer())]
+		bot.act(bot.strategy.NextAction(bot))
+		depth++
+	}
+
+	outcome := evaluate(clone, clone.Players()[me])
+
+	for ; n != nil; n = n.parent {
+		n.visits++
+
+		if n.player == me {
+			n.reward += outcome
+		} else {
+			n.reward += 1 - outcome
+		}
+	}
+}
+
+// pick returns the child for one of the available actions with the highest UCB1 score
+func (n *node) pick(actions []Action) *node {
+	var best *node
+	bestScore := math.Inf(-1)
+
+	for _, a := range actions {
+		child := n.children[a.key()]
+
+		score := math.Inf(1)
+		if child.visits > 0 {
+			score = child.reward/child.visits + exploration*math.Sqrt(math.Log(child.avail)/child.visits)
+		}
+
+		if score > bestScore {
+			best = child
+			bestScore = score
+		}
+	}
+
+	return best
+}
+
+// playouts makes both players of a clone play with the heuristic strategy
+func playouts(m *match.Match) []*Bot {
+	bots := make([]*Bot, 0)
+
+	for _, p := range m.Players() {
+		bot := &Bot{name: p.Name(), match: m, player: p, strategy: NewHeuristic()}
+		p.SetDecider(bot)
+
+		bots = append(bots, bot)
+	}
+
+	return bots
+}
+
+// candidates returns the actions worth searching for the given player, which are its legal actions
+// except for setting down cards that can't ambush
+func candidates(m *match.Match, p *match.Player) []Action {
+	ctx := match.NewContext(m, nil)
+	legal := m.LegalActions(p)
+
+	actions := make([]Action, 0)
+
+	for _, id := range legal.Play {
+		actions = append(actions, Action{Header: "play_card", ID: id})
+	}
+
+	for _, id := range legal.Set {
+		if c, err := match.GetCard(id, p.CollectCards(match.HAND)); err == nil && c.HasHandler(fx.Ambush, ctx) {
+			actions = append(actions, Action{Header: "set_card", ID: id})
+		}
+	}
+
+	for _, attack := range legal.Attacks {
+		if attack.Player {
+			actions = append(actions, Action{Header: "attack_player", ID: attack.ID})
+		}
+
+		for _, target := range attack.Targets {
+			actions = append(actions, Action{Header: "attack_creature", ID: attack.ID, Target: target})
+		}
+	}
+
+	return append(actions, Action{Header: "end_turn"})
+}
+
+// evaluate returns how good the match looks for the player, from 0 for a loss to 1 for a win
+func evaluate(m *match.Match, p *match.Player) float64 {
+	opponent := m.Opponent(p)
+
+	switch {
+	case m.Winner(p):
+		return 1
+	case m.Winner(opponent):
+		return 0
+	case m.Ended():
+		return 0.5
+	}
+
+	ctx := match.NewContext(m, nil)
+
+	score := float64(p.Life() - opponent.Life())
+	score += strength(p, ctx) - strength(opponent, ctx)
+	score += 0.5 * float64(len(p.CollectCards(match.HAND, match.TRAPZONE))-len(opponent.CollectCards(match.HAND, match.TRAPZONE)))
+
+	return 1 / (1 + math.Exp(-score/10))
+}
+
+// strength returns the combined stats of the player's creatures
+func strength(p *match.Player, ctx *match.Context) float64 {
+	total := 0.0
+
+	for _, c := range p.CollectCards(match.BATTLEZONE) {
+		total += float64(c.GetAttack(ctx)+c.GetDefence(ctx)) / 2
+	}
+
+	return total
+}
+
+// fingerprint summarises the visible state of a match, to tell whether an action did anything
+func fingerprint(m *match.Match) string {
+	result := ""
+
+	for _, p := range m.Players() {
+		result += fmt.Sprintf("%d/%d/%v;", p.Life(), p.Turn(), p.IsPlayerTurn())
+
+		for _, container := range match.AllContainers() {
+			for _, c := range p.CollectCards(container) {
+				result += fmt.Sprintf("%s:%s:%v,", c.ID(), c.Zone(), c.Tapped)
+			}
+		}
+	}
+
+	return result
+}
+
+// playerIndex returns the index of the player in Match.Players
+func playerIndex(m *match.Match, p *match.Player) int {
+	for i, player := range m.Players() {
+		if player == p {
+			return i
+		}
+	}
+
+	return -1
+}
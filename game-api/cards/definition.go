@@ -0,0 +1,247 @@
+package cards
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/jyotiskaghosh/ganjifa/game-api/civ"
+	"github.com/jyotiskaghosh/ganjifa/game-api/family"
+	"github.com/jyotiskaghosh/ganjifa/game-api/fx"
+	"github.com/jyotiskaghosh/ganjifa/game-api/match"
+	"github.com/jyotiskaghosh/ganjifa/game-api/script"
+)
+
+// SetDefinition is a set of cards written as data instead of code, such as
+//
+//	{
+//		"set": "set-02",
+//		"cards": [
+//			{
+//				"uid": 100,
+//				"name": "Gaja",
+//				"text": "Gaja gets +1/+1 for each other beast in your battlezone.",
+//				"rank": 1,
+//				"civ": "prithvi",
+//				"family": "beast",
+//				"attack": 3,
+//				"defence": 3,
+//				"effects": [
+//					{"fx": "FamilyBonus", "params": {"family": "beast", "amount": 1}},
+//					{"script": "gaja.star"}
+//				]
+//			}
+//		]
+//	}
+//
+// Every card gets the default behaviour of its family, fx.Spell, fx.Equipment or fx.Creature,
+// and the effects are the keywords and templates registered in fx, or scripts. Scripts are files
+// relative to the file of the set, see the script package for what they can do
+type SetDefinition struct {
+	Set   string           `json:"set"`
+	Cards []CardDefinition `json:"cards"`
+}
+
+// CardDefinition is a card of a SetDefinition
+type CardDefinition struct {
+	UID     int                `json:"uid"`
+	Name    string             `json:"name"`
+	Text    string             `json:"text"`
+	Rank    uint8              `json:"rank"`
+	Civ     civ.Civilisation   `json:"civ"`
+	Family  string             `json:"family"`
+	Attack  uint8              `json:"attack"`
+	Defence uint8              `json:"defence"`
+	Effects []EffectDefinition `json:"effects"`
+}
+
+// EffectDefinition is an effect of a CardDefinition, either a template of fx or a script
+type EffectDefinition struct {
+	Fx     string    `json:"fx"`
+	Params fx.Params `json:"params"`
+	Script string    `json:"script"`
+}
+
+var civilisations = map[civ.Civilisation]bool{
+	civ.PRITHVI: true,
+	civ.APAS:    true,
+	civ.AGNI:    true,
+	civ.VAYU:    true,
+	civ.AKASHA:  true,
+}
+
+var families = map[string]bool{
+	family.Spell:     true,
+	family.Equipment: true,
+	family.Human:     true,
+	family.Fish:      true,
+	family.Beast:     true,
+	family.Bird:      true,
+	family.Ghost:     true,
+	family.Insect:    true,
+}
+
+// Compile checks the definition and returns a constructor for the card
+func (d CardDefinition) Compile() (match.CardConstructor, error) {
+	if d.Name == "" {
+		return nil, fmt.Errorf("card %d has no name", d.UID)
+	}
+
+	if !civilisations[d.Civ] {
+		return nil, fmt.Errorf("card %s has an unknown civilisation %s", d.Name, d.Civ)
+	}
+
+	if !families[d.Family] {
+		return nil, fmt.Errorf("card %s has an unknown family %s", d.Name, d.Family)
+	}
+
+	effects := make([]match.HandlerFunc, 0)
+
+	// A spell whose script chooses targets is played by the script
+	targets := false
+
+	for _, e := range d.Effects {
+		if e.Script != "" {
+			if e.Fx != "" {
+				return nil, fmt.Errorf("card %s: an effect is either fx or a script", d.Name)
+			}
+
+			s, err := script.Load(e.Script)
+			if err != nil {
+				return nil, fmt.Errorf("card %s: %s", d.Name, err)
+			}
+
+			targets = targets || s.Targets()
+			effects = append(effects, s.Handlers()...)
+			continue
+		}
+
+		handler, err := fx.Effect(e.Fx, e.Params)
+		if err != nil {
+			return nil, fmt.Errorf("card %s: %s", d.Name, err)
+		}
+
+		effects = append(effects, handler)
+	}
+
+	switch d.Family {
+	case family.Spell:
+		if !targets {
+			effects = append([]match.HandlerFunc{fx.Spell}, effects...)
+		}
+	case family.Equipment:
+		effects = append([]match.HandlerFunc{fx.Equipment}, effects...)
+	default:
+		effects = append([]match.HandlerFunc{fx.Creature}, effects...)
+	}
+
+	cb := match.CardBuilder{
+		Name:    d.Name,
+		Text:    d.Text,
+		Rank:    d.Rank,
+		Civ:     d.Civ,
+		Family:  d.Family,
+		Attack:  d.Attack,
+		Defence: d.Defence,
+		Effects: effects,
+	}
+
+	return cb.Build, nil
+}
+
+// LoadSet reads a SetDefinition and adds its cards to Sets. Nothing is added if a card is invalid
+// or its uid is already taken. Scripts are relative to the working directory
+func LoadSet(r io.Reader) error {
+	return loadSet(r, "")
+}
+
+// loadSet loads a SetDefinition whose scripts are relative to dir
+func loadSet(r io.Reader, dir string) error {
+	var d SetDefinition
+
+	if err := json.NewDecoder(r).Decode(&d); err != nil {
+		return err
+	}
+
+	if d.Set == "" {
+		return fmt.Errorf("the set has no name")
+	}
+
+	if _, ok := Sets[d.Set]; ok {
+		return fmt.Errorf("set %s already exists", d.Set)
+	}
+
+	set := make(map[int]match.CardConstructor)
+
+	for _, c := range d.Cards {
+		if c.UID < 0 {
+			return fmt.Errorf("card %s has a negative uid", c.Name)
+		}
+
+		if _, ok := set[c.UID]; ok || taken(c.UID) {
+			return fmt.Errorf("card %s has uid %d, which is already taken", c.Name, c.UID)
+		}
+
+		for i, e := range c.Effects {
+			if e.Script != "" && !filepath.IsAbs(e.Script) {
+				c.Effects[i].Script = filepath.Join(dir, e.Script)
+			}
+		}
+
+		ctor, err := c.Compile()
+		if err != nil {
+			return err
+		}
+
+		set[c.UID] = ctor
+	}
+
+	Sets[d.Set] = &set
+
+	return nil
+}
+
+// LoadSets loads every .json file of the directory as a SetDefinition
+func LoadSets(dir string) error {
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+
+	for _, f := range files {
+		if f.IsDir() || filepath.Ext(f.Name()) != ".json" {
+			continue
+		}
+
+		if err := loadSetFile(filepath.Join(dir, f.Name())); err != nil {
+			return fmt.Errorf("%s: %s", f.Name(), err)
+		}
+	}
+
+	return nil
+}
+
+// loadSetFile loads the SetDefinition in the file
+func loadSetFile(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	return loadSet(f, filepath.Dir(path))
+}
+
+// taken returns true if a card of any set has the uid
+func taken(uid int) bool {
+	for _, set := range Sets {
+		if _, ok := (*set)[uid]; ok {
+			return true
+		}
+	}
+
+	return false
+}
@@ -51,6 +51,8 @@ var ctors = []match.CardConstructor{
 	set01.VampireFangs,
 	set01.Whirlwind,
 	set01.WindCloak,
+	// Cards are appended from here on, so that the ids of the cards above don't change
+	set01.Madhumaksika,
 }
 
 // Set01 is a map with all the card id's in the game and corresponding CardConstructor for set01
@@ -14,6 +14,7 @@ import (
 func Salavrka() *match.Card {
 	cb := match.CardBuilder{
 		Name:    "Salavrka",
+		Text:    "Salavrka gets +1/+1 for each other beast in your battlezone.",
 		Rank:    0,
 		Civ:     civ.PRITHVI,
 		Family:  family.Beast,
@@ -21,7 +22,7 @@ func Salavrka() *match.Card {
 		Defence: 1,
 		Effects: []match.HandlerFunc{
 			fx.Creature,
-			func(card *match.Card, ctx *match.Context) {
+			match.Subscribe(func(card *match.Card, ctx *match.Context) {
 				if cards, err := card.Player().Container(match.BATTLEZONE); err == nil {
 					for _, c := range cards {
 						if c != card && c.Family() == family.Beast {
@@ -30,7 +31,7 @@ func Salavrka() *match.Card {
 						}
 					}
 				}
-			},
+			}, &match.ContinuousEvent{}),
 		},
 	}
 
@@ -58,6 +59,7 @@ func Vanara() *match.Card {
 func Krostr() *match.Card {
 	cb := match.CardBuilder{
 		Name:    "Krostr",
+		Text:    "When Krostr enters your battlezone, you may search your deck for a beast and put it into your hand.",
 		Rank:    1,
 		Civ:     civ.PRITHVI,
 		Family:  family.Beast,
@@ -65,36 +67,34 @@ func Krostr() *match.Card {
 		Defence: 2,
 		Effects: []match.HandlerFunc{
 			fx.Creature,
-			func(card *match.Card, ctx *match.Context) {
-				if card.AmIPlayed(ctx) {
-					ctx.ScheduleAfter(func() {
-						cards, err := card.Player().Container(match.DECK)
-						if err != nil {
-							ctx.InterruptFlow()
+			fx.OnEnterBattlezone(func(card *match.Card, ctx *match.Context) {
+				ctx.Match().Push(card, fmt.Sprintf("%s searches %s's deck for a %s", card.Name(), card.Player().Name(), family.Beast), func() {
+					cards, err := card.Player().Container(match.DECK)
+					if err != nil {
+						ctx.InterruptFlow()
+						logrus.Debug(err)
+						return
+					}
+
+					cards = card.Player().Search(
+						match.Filter(cards, func(x *match.Card) bool { return x.Family() == family.Beast }),
+						fmt.Sprintf("Select 1 %s", family.Beast),
+						1,
+						1,
+						true)
+
+					for _, c := range cards {
+						if err := c.MoveCard(match.HAND); err != nil {
 							logrus.Debug(err)
 							return
 						}
 
-						cards = card.Player().Search(
-							match.Filter(cards, func(x *match.Card) bool { return x.Family() == family.Beast }),
-							fmt.Sprintf("Select 1 %s", family.Beast),
-							1,
-							1,
-							true)
-
-						for _, c := range cards {
-							if err := c.MoveCard(match.HAND); err != nil {
-								logrus.Debug(err)
-								return
-							}
-
-							ctx.Match().Chat("Server", fmt.Sprintf("%s was moved from %s's deck to their hand", c.Name(), card.Player().Name()))
-						}
+						ctx.Match().Chat("Server", fmt.Sprintf("%s was moved from %s's deck to their hand", c.Name(), card.Player().Name()))
+					}
 
-						card.Player().ShuffleDeck()
-					})
-				}
-			},
+					card.Player().ShuffleDeck()
+				})
+			}),
 		},
 	}
 
@@ -105,6 +105,7 @@ func Krostr() *match.Card {
 func Dvipin() *match.Card {
 	cb := match.CardBuilder{
 		Name:    "Dvipin",
+		Text:    "Ambush: when your opponent attacks, you may play Dvipin from your set down cards and have it battle the attacker.",
 		Rank:    1,
 		Civ:     civ.PRITHVI,
 		Family:  family.Beast,
@@ -28,6 +28,7 @@ func Kukkutah() *match.Card {
 func Cataka() *match.Card {
 	cb := match.CardBuilder{
 		Name:    "Cataka",
+		Text:    "Cataka can't be attacked.",
 		Rank:    0,
 		Civ:     civ.VAYU,
 		Family:  family.Bird,
@@ -46,6 +47,7 @@ func Cataka() *match.Card {
 func Syena() *match.Card {
 	cb := match.CardBuilder{
 		Name:    "Syena",
+		Text:    "Syena gets +4 attack while attacking a player.",
 		Rank:    1,
 		Civ:     civ.VAYU,
 		Family:  family.Bird,
@@ -53,13 +55,13 @@ func Syena() *match.Card {
 		Defence: 3,
 		Effects: []match.HandlerFunc{
 			fx.Creature,
-			func(card *match.Card, ctx *match.Context) {
+			match.Subscribe(func(card *match.Card, ctx *match.Context) {
 				if event, ok := ctx.Event().(*match.GetAttackEvent); ok && event.ID == card.ID() {
 					if _, ok := event.Event.(*match.AttackPlayer); ok {
 						event.Attack += 4
 					}
 				}
-			},
+			}, &match.GetAttackEvent{}),
 		},
 	}
 
@@ -70,6 +72,7 @@ func Syena() *match.Card {
 func Atayi() *match.Card {
 	cb := match.CardBuilder{
 		Name:    "Atayi",
+		Text:    "Whenever your opponent takes damage, Atayi can't be blocked until your next turn.",
 		Rank:    1,
 		Civ:     civ.VAYU,
 		Family:  family.Bird,
@@ -77,11 +80,11 @@ func Atayi() *match.Card {
 		Defence: 2,
 		Effects: []match.HandlerFunc{
 			fx.Creature,
-			func(card *match.Card, ctx *match.Context) {
+			match.Subscribe(func(card *match.Card, ctx *match.Context) {
 				if event, ok := ctx.Event().(*match.DamageEvent); ok && card.Zone() == match.BATTLEZONE && event.Player != card.Player() {
-					card.AddCondition(fx.CantBeBlocked)
+					card.AddCondition(match.UntilYourNextTurn, fx.CantBeBlocked)
 				}
-			},
+			}, &match.DamageEvent{}),
 		},
 	}
 
@@ -11,6 +11,7 @@ import (
 func Churika() *match.Card {
 	cb := match.CardBuilder{
 		Name:   "Churika",
+		Text:   "The equipped creature gets +2 attack.",
 		Rank:   0,
 		Civ:    civ.AGNI,
 		Family: family.Equipment,
@@ -27,6 +28,7 @@ func Churika() *match.Card {
 func Khadga() *match.Card {
 	cb := match.CardBuilder{
 		Name:   "Khadga",
+		Text:   "The equipped creature gets +4 attack.",
 		Rank:   1,
 		Civ:    civ.AGNI,
 		Family: family.Equipment,
@@ -43,17 +45,18 @@ func Khadga() *match.Card {
 func VampireFangs() *match.Card {
 	cb := match.CardBuilder{
 		Name:   "Vampire Fangs",
+		Text:   "The equipped creature gets +2 attack and leech.",
 		Rank:   1,
 		Civ:    civ.PRITHVI,
 		Family: family.Equipment,
 		Attack: 2,
 		Effects: []match.HandlerFunc{
 			fx.Equipment,
-			func(card *match.Card, ctx *match.Context) {
+			match.Subscribe(func(card *match.Card, ctx *match.Context) {
 				if card.AttachedTo() != nil {
 					fx.Leech(card.AttachedTo(), ctx)
 				}
-			},
+			}, &match.DamageEvent{}, &match.CreatureDestroyed{}),
 		},
 	}
 
@@ -64,16 +67,17 @@ func VampireFangs() *match.Card {
 func WindCloak() *match.Card {
 	cb := match.CardBuilder{
 		Name:   "Wind Cloak",
+		Text:   "The equipped creature can't be attacked.",
 		Rank:   0,
 		Civ:    civ.VAYU,
 		Family: family.Equipment,
 		Effects: []match.HandlerFunc{
 			fx.Equipment,
-			func(card *match.Card, ctx *match.Context) {
+			match.Subscribe(func(card *match.Card, ctx *match.Context) {
 				if card.AttachedTo() != nil {
 					fx.CantBeAttacked(card.AttachedTo(), ctx)
 				}
-			},
+			}, &match.AttackCreature{}),
 		},
 	}
 
@@ -84,20 +88,21 @@ func WindCloak() *match.Card {
 func ScopeLens() *match.Card {
 	cb := match.CardBuilder{
 		Name:   "Scope Lens",
+		Text:   "The equipped creature gets +1 attack, and another +2 while attacking a player.",
 		Rank:   0,
 		Civ:    civ.VAYU,
 		Family: family.Equipment,
 		Attack: 1,
 		Effects: []match.HandlerFunc{
 			fx.Equipment,
-			func(card *match.Card, ctx *match.Context) {
+			match.Subscribe(func(card *match.Card, ctx *match.Context) {
 				if event, ok := ctx.Event().(*match.GetAttackEvent); ok &&
 					card.AttachedTo() != nil && event.ID == card.AttachedTo().ID() {
 					if _, ok := event.Event.(*match.AttackPlayer); ok {
 						event.Attack += 2
 					}
 				}
-			},
+			}, &match.GetAttackEvent{}),
 		},
 	}
 
@@ -108,6 +113,7 @@ func ScopeLens() *match.Card {
 func ShellArmor() *match.Card {
 	cb := match.CardBuilder{
 		Name:    "Shell Armor",
+		Text:    "The equipped creature gets +2 defence.",
 		Rank:    0,
 		Civ:     civ.APAS,
 		Family:  family.Equipment,
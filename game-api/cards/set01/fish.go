@@ -11,6 +11,7 @@ import (
 func Matsyaka() *match.Card {
 	cb := match.CardBuilder{
 		Name:    "Matsyaka",
+		Text:    "A creature evolved from Matsyaka gets +1/+1.",
 		Rank:    0,
 		Civ:     civ.APAS,
 		Family:  family.Fish,
@@ -18,19 +19,14 @@ func Matsyaka() *match.Card {
 		Defence: 1,
 		Effects: []match.HandlerFunc{
 			fx.Creature,
-			func(card *match.Card, ctx *match.Context) {
-				if event, ok := ctx.Event().(*match.GetAttackEvent); ok {
+			match.Subscribe(func(card *match.Card, ctx *match.Context) {
+				if event, ok := ctx.Event().(*match.ContinuousEvent); ok && event.Layer == match.ModifierLayer {
 					if card.AttachedTo() != nil && event.ID == card.AttachedTo().ID() {
-						event.Attack++
+						event.Stats.Attack++
+						event.Stats.Defence++
 					}
 				}
-
-				if event, ok := ctx.Event().(*match.GetDefenceEvent); ok {
-					if card.AttachedTo() != nil && event.ID == card.AttachedTo().ID() {
-						event.Defence++
-					}
-				}
-			},
+			}, &match.ContinuousEvent{}),
 		},
 	}
 
@@ -41,6 +37,7 @@ func Matsyaka() *match.Card {
 func DeadlyZebrafish() *match.Card {
 	cb := match.CardBuilder{
 		Name:    "Deadly Zebrafish",
+		Text:    "Poisonous: a creature that attacks Deadly Zebrafish, or is blocked by it, is destroyed at the end of the turn.",
 		Rank:    0,
 		Civ:     civ.APAS,
 		Family:  family.Fish,
@@ -59,6 +56,7 @@ func DeadlyZebrafish() *match.Card {
 func TorpedoingBarracuda() *match.Card {
 	cb := match.CardBuilder{
 		Name:    "Torpedoing Barracuda",
+		Text:    "Torpedoing Barracuda can't be blocked.",
 		Rank:    1,
 		Civ:     civ.APAS,
 		Family:  family.Fish,
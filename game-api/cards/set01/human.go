@@ -14,6 +14,7 @@ import (
 func Ayudhabhrt() *match.Card {
 	cb := match.CardBuilder{
 		Name:    "Ayudhabhrt",
+		Text:    "Ayudhabhrt gets +1/+1 for each equipment attached to it.",
 		Rank:    0,
 		Civ:     civ.AGNI,
 		Family:  family.Human,
@@ -21,14 +22,14 @@ func Ayudhabhrt() *match.Card {
 		Defence: 1,
 		Effects: []match.HandlerFunc{
 			fx.Creature,
-			func(card *match.Card, ctx *match.Context) {
+			match.Subscribe(func(card *match.Card, ctx *match.Context) {
 				for _, c := range card.Attachments() {
 					if c.Family() == family.Equipment {
 						fx.AttackModifier(card, ctx, 1)
 						fx.DefenceModifier(card, ctx, 1)
 					}
 				}
-			},
+			}, &match.ContinuousEvent{}),
 		},
 	}
 
@@ -56,6 +57,7 @@ func Sainika() *match.Card {
 func Sastravikrayin() *match.Card {
 	cb := match.CardBuilder{
 		Name:    "Sastravikrayin",
+		Text:    "When Sastravikrayin enters your battlezone, you may search your deck for an equipment and put it into your hand.",
 		Rank:    1,
 		Civ:     civ.AGNI,
 		Family:  family.Human,
@@ -63,35 +65,33 @@ func Sastravikrayin() *match.Card {
 		Defence: 2,
 		Effects: []match.HandlerFunc{
 			fx.Creature,
-			func(card *match.Card, ctx *match.Context) {
-				if card.AmIPlayed(ctx) {
-					ctx.ScheduleAfter(func() {
-						cards, err := card.Player().Container(match.DECK)
-						if err != nil {
-							ctx.InterruptFlow()
-							logrus.Debug(err)
-							return
-						}
+			fx.OnEnterBattlezone(func(card *match.Card, ctx *match.Context) {
+				ctx.Match().Push(card, fmt.Sprintf("%s searches %s's deck for a %s", card.Name(), card.Player().Name(), family.Equipment), func() {
+					cards, err := card.Player().Container(match.DECK)
+					if err != nil {
+						ctx.InterruptFlow()
+						logrus.Debug(err)
+						return
+					}
 
-						cards = card.Player().Search(
-							match.Filter(cards, func(x *match.Card) bool { return x.Family() == family.Equipment }),
-							fmt.Sprintf("Select 1 %s", family.Equipment),
-							1,
-							1,
-							true)
+					cards = card.Player().Search(
+						match.Filter(cards, func(x *match.Card) bool { return x.Family() == family.Equipment }),
+						fmt.Sprintf("Select 1 %s", family.Equipment),
+						1,
+						1,
+						true)
 
-						for _, c := range cards {
-							if err := c.MoveCard(match.HAND); err != nil {
-								logrus.Debug(err)
-								return
-							}
-							ctx.Match().Chat("Server", fmt.Sprintf("%s was moved from %s's deck to their hand", c.Name(), card.Player().Name()))
+					for _, c := range cards {
+						if err := c.MoveCard(match.HAND); err != nil {
+							logrus.Debug(err)
+							return
 						}
+						ctx.Match().Chat("Server", fmt.Sprintf("%s was moved from %s's deck to their hand", c.Name(), card.Player().Name()))
+					}
 
-						card.Player().ShuffleDeck()
-					})
-				}
-			},
+					card.Player().ShuffleDeck()
+				})
+			}),
 		},
 	}
 
@@ -102,6 +102,7 @@ func Sastravikrayin() *match.Card {
 func Astrakara() *match.Card {
 	cb := match.CardBuilder{
 		Name:    "Astrakara",
+		Text:    "While Astrakara is in your battlezone, the equipment in your hand has 1 less rank.",
 		Rank:    1,
 		Civ:     civ.AGNI,
 		Family:  family.Human,
@@ -109,19 +110,20 @@ func Astrakara() *match.Card {
 		Defence: 2,
 		Effects: []match.HandlerFunc{
 			fx.Creature,
-			func(card *match.Card, ctx *match.Context) {
-				if event, ok := ctx.Event().(*match.GetRankEvent); ok && card.Zone() == match.BATTLEZONE {
+			match.Subscribe(func(card *match.Card, ctx *match.Context) {
+				if event, ok := ctx.Event().(*match.ContinuousEvent); ok &&
+					event.Layer == match.CharacteristicLayer && card.Zone() == match.BATTLEZONE {
 					card, err := match.GetCard(event.ID, card.Player().CollectCards(match.HAND))
 					if err != nil {
 						logrus.Debug(err)
 						return
 					}
 
-					if card.Family() == family.Equipment && event.Rank > 0 {
-						event.Rank--
+					if card.Family() == family.Equipment && event.Stats.Rank > 0 {
+						event.Stats.Rank--
 					}
 				}
-			},
+			}, &match.ContinuousEvent{}),
 		},
 	}
 
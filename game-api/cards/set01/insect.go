@@ -10,10 +10,28 @@ import (
 	"github.com/sirupsen/logrus"
 )
 
+func init() {
+	match.RegisterToken(maksika)
+}
+
+// maksika is the insect token created by Madhumaksika
+var maksika = match.CardBuilder{
+	Name:    "Maksika",
+	Rank:    0,
+	Civ:     civ.PRITHVI,
+	Family:  family.Insect,
+	Attack:  1,
+	Defence: 1,
+	Effects: []match.HandlerFunc{
+		fx.Creature,
+	},
+}
+
 // Pipilika ...
 func Pipilika() *match.Card {
 	cb := match.CardBuilder{
 		Name:    "Pipilika",
+		Text:    "Pipilika's attack is doubled.",
 		Rank:    0,
 		Civ:     civ.PRITHVI,
 		Family:  family.Insect,
@@ -21,13 +39,11 @@ func Pipilika() *match.Card {
 		Defence: 1,
 		Effects: []match.HandlerFunc{
 			fx.Creature,
-			func(card *match.Card, ctx *match.Context) {
-				if event, ok := ctx.Event().(*match.GetAttackEvent); ok && event.ID == card.ID() {
-					ctx.ScheduleAfter(func() {
-						event.Attack *= 2
-					})
+			match.Subscribe(func(card *match.Card, ctx *match.Context) {
+				if event, ok := ctx.Event().(*match.ContinuousEvent); ok && event.Layer == match.MultiplierLayer && event.ID == card.ID() {
+					event.Stats.Attack *= 2
 				}
-			},
+			}, &match.ContinuousEvent{}),
 		},
 	}
 
@@ -38,6 +54,7 @@ func Pipilika() *match.Card {
 func Masaka() *match.Card {
 	cb := match.CardBuilder{
 		Name:    "Masaka",
+		Text:    "Leech: whenever Masaka deals damage to a player you gain that much life, and whenever it destroys a creature you gain life equal to its defence.",
 		Rank:    0,
 		Civ:     civ.PRITHVI,
 		Family:  family.Insect,
@@ -56,44 +73,49 @@ func Masaka() *match.Card {
 func MahisiPipilika() *match.Card {
 	cb := match.CardBuilder{
 		Name:    "Mahisi Pipilika",
+		Text:    "At the start of your turn, search your deck for an insect and put it into your hand. Your other insects get +1/+1.",
 		Rank:    1,
 		Civ:     civ.PRITHVI,
 		Family:  family.Insect,
 		Defence: 2,
 		Effects: []match.HandlerFunc{
 			fx.Creature,
-			func(card *match.Card, ctx *match.Context) {
+			match.Subscribe(func(card *match.Card, ctx *match.Context) {
 				if card.Zone() != match.BATTLEZONE {
 					return
 				}
 
 				if _, ok := ctx.Event().(*match.BeginTurnStep); ok && card.Player().IsPlayerTurn() {
-					cards, err := card.Player().Container(match.DECK)
-					if err != nil {
-						ctx.InterruptFlow()
-						logrus.Debug(err)
-						return
-					}
-
-					cards = card.Player().Search(
-						match.Filter(cards, func(x *match.Card) bool { return x.Family() == family.Insect }),
-						fmt.Sprintf("Select 1 %s", family.Insect),
-						1,
-						1,
-						false)
-
-					for _, c := range cards {
-						if err := c.MoveCard(match.HAND); err != nil {
-							logrus.Debug(err)
-							return
-						}
-						ctx.Match().Chat("Server", fmt.Sprintf("%s was moved from %s's deck to their hand", c.Name(), card.Player().Name()))
-					}
-
-					card.Player().ShuffleDeck()
+					ctx.ScheduleAfter(func() {
+						ctx.Match().Push(card, fmt.Sprintf("%s searches %s's deck for a %s", card.Name(), card.Player().Name(), family.Insect), func() {
+							cards, err := card.Player().Container(match.DECK)
+							if err != nil {
+								ctx.InterruptFlow()
+								logrus.Debug(err)
+								return
+							}
+
+							cards = card.Player().Search(
+								match.Filter(cards, func(x *match.Card) bool { return x.Family() == family.Insect }),
+								fmt.Sprintf("Select 1 %s", family.Insect),
+								1,
+								1,
+								false)
+
+							for _, c := range cards {
+								if err := c.MoveCard(match.HAND); err != nil {
+									logrus.Debug(err)
+									return
+								}
+								ctx.Match().Chat("Server", fmt.Sprintf("%s was moved from %s's deck to their hand", c.Name(), card.Player().Name()))
+							}
+
+							card.Player().ShuffleDeck()
+						})
+					})
 				}
 
-				if event, ok := ctx.Event().(*match.GetAttackEvent); ok && event.ID != card.ID() {
+				if event, ok := ctx.Event().(*match.ContinuousEvent); ok && event.Layer == match.ModifierLayer && event.ID != card.ID() {
 					card, err := match.GetCard(event.ID, card.Player().CollectCards(match.BATTLEZONE))
 					if err != nil {
 						logrus.Debug(err)
@@ -101,22 +123,40 @@ func MahisiPipilika() *match.Card {
 					}
 
 					if card.HasFamily(family.Insect, ctx) {
-						event.Attack++
+						event.Stats.Attack++
+						event.Stats.Defence++
 					}
 				}
+			}, &match.BeginTurnStep{}, &match.ContinuousEvent{}),
+		},
+	}
 
-				if event, ok := ctx.Event().(*match.GetDefenceEvent); ok && event.ID != card.ID() {
-					card, err := match.GetCard(event.ID, card.Player().CollectCards(match.BATTLEZONE))
-					if err != nil {
-						logrus.Debug(err)
-						return
-					}
+	return cb.Build()
+}
 
-					if card.HasFamily(family.Insect, ctx) {
-						event.Defence++
+// Madhumaksika ...
+func Madhumaksika() *match.Card {
+	cb := match.CardBuilder{
+		Name:    "Madhumaksika",
+		Text:    "When Madhumaksika enters your battlezone, summon 2 Maksika, 1/1 insect tokens.",
+		Rank:    0,
+		Civ:     civ.PRITHVI,
+		Family:  family.Insect,
+		Attack:  1,
+		Defence: 1,
+		Effects: []match.HandlerFunc{
+			fx.Creature,
+			fx.OnEnterBattlezone(func(card *match.Card, ctx *match.Context) {
+				ctx.Match().Push(card, fmt.Sprintf("%s summons 2 %s", card.Name(), maksika.Name), func() {
+					for i := 0; i < 2; i++ {
+						if _, err := card.Player().CreateToken(maksika.Name); err != nil {
+							logrus.Debug(err)
+							return
+						}
 					}
-				}
-			},
+					ctx.Match().Chat("Server", fmt.Sprintf("%s summoned 2 %s", card.Player().Name(), maksika.Name))
+				})
+			}),
 		},
 	}
 
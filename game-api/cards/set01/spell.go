@@ -11,15 +11,31 @@ import (
 	"github.com/jyotiskaghosh/ganjifa/game-api/match"
 )
 
+func init() {
+	match.RegisterCondition("set01.EnergySurge", match.Subscribe(energySurge, &match.ContinuousEvent{}))
+	match.RegisterCondition("set01.LeechLife", match.Subscribe(leechLife, &match.ContinuousEvent{}))
+}
+
+// energySurge is the attack bonus given by Energy Surge
+func energySurge(card *match.Card, ctx *match.Context) {
+	fx.AttackModifier(card, ctx, 4)
+}
+
+// leechLife is the attack bonus given by Leech Life
+func leechLife(card *match.Card, ctx *match.Context) {
+	fx.AttackModifier(card, ctx, 2)
+}
+
 // EnergySurge ...
 func EnergySurge() *match.Card {
 	cb := match.CardBuilder{
 		Name:   "Energy Surge",
+		Text:   "1 of your creatures gets +4 attack until the end of the turn.",
 		Rank:   0,
 		Civ:    civ.AGNI,
 		Family: family.Spell,
 		Effects: []match.HandlerFunc{
-			func(card *match.Card, ctx *match.Context) {
+			match.Subscribe(func(card *match.Card, ctx *match.Context) {
 				switch event := ctx.Event().(type) {
 				case *match.PlayCardEvent:
 					if event.ID == card.ID() {
@@ -39,16 +55,14 @@ func EnergySurge() *match.Card {
 					if event.ID == card.ID() {
 						ctx.ScheduleAfter(func() {
 							for _, c := range event.Targets {
-								c.AddCondition(func(card *match.Card, ctx *match.Context) {
-									fx.AttackModifier(card, ctx, 4)
-								})
+								c.AddCondition(match.UntilEndOfTurn, energySurge)
 							}
 						})
 					}
 				default:
 					fx.Spell(card, ctx)
 				}
-			},
+			}, &match.PlayCardEvent{}, &match.SpellCast{}, &match.TrapEvent{}),
 		},
 	}
 
@@ -59,11 +73,12 @@ func EnergySurge() *match.Card {
 func Fireball() *match.Card {
 	cb := match.CardBuilder{
 		Name:   "Fireball",
+		Text:   "Destroy 1 of your opponent's creatures with 2 or less defence.",
 		Rank:   0,
 		Civ:    civ.AGNI,
 		Family: family.Spell,
 		Effects: []match.HandlerFunc{
-			func(card *match.Card, ctx *match.Context) {
+			match.Subscribe(func(card *match.Card, ctx *match.Context) {
 				switch event := ctx.Event().(type) {
 				case *match.PlayCardEvent:
 					if event.ID == card.ID() {
@@ -94,7 +109,7 @@ func Fireball() *match.Card {
 				default:
 					fx.Spell(card, ctx)
 				}
-			},
+			}, &match.PlayCardEvent{}, &match.SpellCast{}, &match.TrapEvent{}),
 		},
 	}
 
@@ -105,12 +120,13 @@ func Fireball() *match.Card {
 func RainOfArrows() *match.Card {
 	cb := match.CardBuilder{
 		Name:   "Rain Of Arrows",
+		Text:   "Destroy every creature with 1 or less defence.",
 		Rank:   0,
 		Civ:    civ.AGNI,
 		Family: family.Spell,
 		Effects: []match.HandlerFunc{
 			fx.Spell,
-			func(card *match.Card, ctx *match.Context) {
+			match.Subscribe(func(card *match.Card, ctx *match.Context) {
 				if event, ok := ctx.Event().(*match.SpellCast); ok && event.ID == card.ID() {
 					ctx.ScheduleAfter(func() {
 						for _, c := range append(
@@ -122,7 +138,7 @@ func RainOfArrows() *match.Card {
 						}
 					})
 				}
-			},
+			}, &match.SpellCast{}),
 		},
 	}
 
@@ -133,11 +149,12 @@ func RainOfArrows() *match.Card {
 func MagmaGeyser() *match.Card {
 	cb := match.CardBuilder{
 		Name:   "MagmaGeyser",
+		Text:   "Destroy 1 of your opponent's creatures with 4 or less defence.",
 		Rank:   1,
 		Civ:    civ.AGNI,
 		Family: family.Spell,
 		Effects: []match.HandlerFunc{
-			func(card *match.Card, ctx *match.Context) {
+			match.Subscribe(func(card *match.Card, ctx *match.Context) {
 				switch event := ctx.Event().(type) {
 				case *match.PlayCardEvent:
 					if event.ID == card.ID() {
@@ -168,7 +185,7 @@ func MagmaGeyser() *match.Card {
 				default:
 					fx.Spell(card, ctx)
 				}
-			},
+			}, &match.PlayCardEvent{}, &match.SpellCast{}, &match.TrapEvent{}),
 		},
 	}
 
@@ -179,11 +196,12 @@ func MagmaGeyser() *match.Card {
 func Degenerate() *match.Card {
 	cb := match.CardBuilder{
 		Name:   "Degenerate",
+		Text:   "Devolve a creature.",
 		Rank:   0,
 		Civ:    civ.PRITHVI,
 		Family: family.Spell,
 		Effects: []match.HandlerFunc{
-			func(card *match.Card, ctx *match.Context) {
+			match.Subscribe(func(card *match.Card, ctx *match.Context) {
 				switch event := ctx.Event().(type) {
 				case *match.PlayCardEvent:
 					if event.ID == card.ID() {
@@ -213,7 +231,7 @@ func Degenerate() *match.Card {
 				default:
 					fx.Spell(card, ctx)
 				}
-			},
+			}, &match.PlayCardEvent{}, &match.SpellCast{}, &match.TrapEvent{}),
 		},
 	}
 
@@ -224,11 +242,12 @@ func Degenerate() *match.Card {
 func LeechLife() *match.Card {
 	cb := match.CardBuilder{
 		Name:   "Leech Life",
+		Text:   "1 of your creatures gets +2 attack and leech until the end of the turn.",
 		Rank:   0,
 		Civ:    civ.PRITHVI,
 		Family: family.Spell,
 		Effects: []match.HandlerFunc{
-			func(card *match.Card, ctx *match.Context) {
+			match.Subscribe(func(card *match.Card, ctx *match.Context) {
 				switch event := ctx.Event().(type) {
 				case *match.PlayCardEvent:
 					if event.ID == card.ID() {
@@ -248,17 +267,14 @@ func LeechLife() *match.Card {
 					if event.ID == card.ID() {
 						ctx.ScheduleAfter(func() {
 							for _, c := range event.Targets {
-								c.AddCondition(func(card *match.Card, ctx *match.Context) {
-									fx.AttackModifier(card, ctx, 2)
-								})
-								c.AddCondition(fx.Leech)
+								c.AddCondition(match.UntilEndOfTurn, leechLife, fx.Leech)
 							}
 						})
 					}
 				default:
 					fx.Spell(card, ctx)
 				}
-			},
+			}, &match.PlayCardEvent{}, &match.SpellCast{}, &match.TrapEvent{}),
 		},
 	}
 
@@ -269,11 +285,12 @@ func LeechLife() *match.Card {
 func RapidEvolution() *match.Card {
 	cb := match.CardBuilder{
 		Name:   "Rapid Evolution",
+		Text:   "Tap a creature. It may evolve again this turn.",
 		Rank:   0,
 		Civ:    civ.PRITHVI,
 		Family: family.Spell,
 		Effects: []match.HandlerFunc{
-			func(card *match.Card, ctx *match.Context) {
+			match.Subscribe(func(card *match.Card, ctx *match.Context) {
 				switch event := ctx.Event().(type) {
 				case *match.PlayCardEvent:
 					if event.ID == card.ID() {
@@ -296,7 +313,7 @@ func RapidEvolution() *match.Card {
 					if event.ID == card.ID() {
 						ctx.ScheduleAfter(func() {
 							for _, c := range event.Targets {
-								c.Tapped = true
+								c.SetTapped(true)
 								c.RemoveCondition(fx.CantEvolve)
 							}
 						})
@@ -304,7 +321,7 @@ func RapidEvolution() *match.Card {
 				default:
 					fx.Spell(card, ctx)
 				}
-			},
+			}, &match.PlayCardEvent{}, &match.SpellCast{}, &match.TrapEvent{}),
 		},
 	}
 
@@ -315,12 +332,13 @@ func RapidEvolution() *match.Card {
 func AirMail() *match.Card {
 	cb := match.CardBuilder{
 		Name:   "Air Mail",
+		Text:   "Search your deck for a card and put it into your hand.",
 		Rank:   0,
 		Civ:    civ.VAYU,
 		Family: family.Spell,
 		Effects: []match.HandlerFunc{
 			fx.Spell,
-			func(card *match.Card, ctx *match.Context) {
+			match.Subscribe(func(card *match.Card, ctx *match.Context) {
 				if event, ok := ctx.Event().(*match.SpellCast); ok && event.ID == card.ID() {
 					ctx.ScheduleAfter(func() {
 						cards := card.Player().Search(
@@ -342,7 +360,7 @@ func AirMail() *match.Card {
 						card.Player().ShuffleDeck()
 					})
 				}
-			},
+			}, &match.SpellCast{}),
 		},
 	}
 
@@ -353,11 +371,12 @@ func AirMail() *match.Card {
 func Whirlwind() *match.Card {
 	cb := match.CardBuilder{
 		Name:   "Whirlwind",
+		Text:   "Destroy 1 of your opponent's set down cards.",
 		Rank:   0,
 		Civ:    civ.VAYU,
 		Family: family.Spell,
 		Effects: []match.HandlerFunc{
-			func(card *match.Card, ctx *match.Context) {
+			match.Subscribe(func(card *match.Card, ctx *match.Context) {
 				switch event := ctx.Event().(type) {
 				case *match.PlayCardEvent:
 					if event.ID == card.ID() {
@@ -387,7 +406,7 @@ func Whirlwind() *match.Card {
 				default:
 					fx.Spell(card, ctx)
 				}
-			},
+			}, &match.PlayCardEvent{}, &match.SpellCast{}, &match.TrapEvent{}),
 		},
 	}
 
@@ -398,11 +417,12 @@ func Whirlwind() *match.Card {
 func Tailwind() *match.Card {
 	cb := match.CardBuilder{
 		Name:   "Tailwind",
+		Text:   "1 of your creatures can't be blocked until the end of the turn.",
 		Rank:   0,
 		Civ:    civ.VAYU,
 		Family: family.Spell,
 		Effects: []match.HandlerFunc{
-			func(card *match.Card, ctx *match.Context) {
+			match.Subscribe(func(card *match.Card, ctx *match.Context) {
 				switch event := ctx.Event().(type) {
 				case *match.PlayCardEvent:
 					if event.ID == card.ID() {
@@ -422,14 +442,14 @@ func Tailwind() *match.Card {
 					if event.ID == card.ID() {
 						ctx.ScheduleAfter(func() {
 							for _, c := range event.Targets {
-								c.AddCondition(fx.CantBeBlocked)
+								c.AddCondition(match.UntilEndOfTurn, fx.CantBeBlocked)
 							}
 						})
 					}
 				default:
 					fx.Spell(card, ctx)
 				}
-			},
+			}, &match.PlayCardEvent{}, &match.SpellCast{}, &match.TrapEvent{}),
 		},
 	}
 
@@ -440,11 +460,12 @@ func Tailwind() *match.Card {
 func Tornado() *match.Card {
 	cb := match.CardBuilder{
 		Name:   "Tornado",
+		Text:   "Put 1 of your opponent's creatures into their deck.",
 		Rank:   1,
 		Civ:    civ.VAYU,
 		Family: family.Spell,
 		Effects: []match.HandlerFunc{
-			func(card *match.Card, ctx *match.Context) {
+			match.Subscribe(func(card *match.Card, ctx *match.Context) {
 				switch event := ctx.Event().(type) {
 				case *match.PlayCardEvent:
 					if event.ID == card.ID() {
@@ -473,7 +494,7 @@ func Tornado() *match.Card {
 				default:
 					fx.Spell(card, ctx)
 				}
-			},
+			}, &match.PlayCardEvent{}, &match.SpellCast{}, &match.TrapEvent{}),
 		},
 	}
 
@@ -484,11 +505,12 @@ func Tornado() *match.Card {
 func FrostBreath() *match.Card {
 	cb := match.CardBuilder{
 		Name:   "FrostBreath",
+		Text:   "Tap 1 of your opponent's creatures.",
 		Rank:   0,
 		Civ:    civ.APAS,
 		Family: family.Spell,
 		Effects: []match.HandlerFunc{
-			func(card *match.Card, ctx *match.Context) {
+			match.Subscribe(func(card *match.Card, ctx *match.Context) {
 				switch event := ctx.Event().(type) {
 				case *match.PlayCardEvent:
 					if event.ID == card.ID() {
@@ -508,14 +530,14 @@ func FrostBreath() *match.Card {
 					if event.ID == card.ID() {
 						ctx.ScheduleAfter(func() {
 							for _, c := range event.Targets {
-								c.Tapped = true
+								c.SetTapped(true)
 							}
 						})
 					}
 				default:
 					fx.Spell(card, ctx)
 				}
-			},
+			}, &match.PlayCardEvent{}, &match.SpellCast{}, &match.TrapEvent{}),
 		},
 	}
 
@@ -526,18 +548,19 @@ func FrostBreath() *match.Card {
 func TidalWave() *match.Card {
 	cb := match.CardBuilder{
 		Name:   "Tidal Wave",
+		Text:   "Draw 2 cards.",
 		Rank:   1,
 		Civ:    civ.APAS,
 		Family: family.Spell,
 		Effects: []match.HandlerFunc{
 			fx.Spell,
-			func(card *match.Card, ctx *match.Context) {
+			match.Subscribe(func(card *match.Card, ctx *match.Context) {
 				if event, ok := ctx.Event().(*match.SpellCast); ok && event.ID == card.ID() {
 					ctx.ScheduleAfter(func() {
 						card.Player().DrawCards(2)
 					})
 				}
-			},
+			}, &match.SpellCast{}),
 		},
 	}
 
@@ -548,18 +571,19 @@ func TidalWave() *match.Card {
 func Amrita() *match.Card {
 	cb := match.CardBuilder{
 		Name:   "Amrita",
+		Text:   "Gain 8 life.",
 		Rank:   1,
 		Civ:    civ.APAS,
 		Family: family.Spell,
 		Effects: []match.HandlerFunc{
 			fx.Spell,
-			func(card *match.Card, ctx *match.Context) {
+			match.Subscribe(func(card *match.Card, ctx *match.Context) {
 				if event, ok := ctx.Event().(*match.SpellCast); ok && event.ID == card.ID() {
 					ctx.ScheduleAfter(func() {
 						card.Player().Heal(card, ctx, 8)
 					})
 				}
-			},
+			}, &match.SpellCast{}),
 		},
 	}
 
@@ -570,20 +594,21 @@ func Amrita() *match.Card {
 func Blizzard() *match.Card {
 	cb := match.CardBuilder{
 		Name:   "Blizzard",
+		Text:   "Tap all of your opponent's creatures.",
 		Rank:   2,
 		Civ:    civ.APAS,
 		Family: family.Spell,
 		Effects: []match.HandlerFunc{
 			fx.Spell,
-			func(card *match.Card, ctx *match.Context) {
+			match.Subscribe(func(card *match.Card, ctx *match.Context) {
 				if event, ok := ctx.Event().(*match.SpellCast); ok && event.ID == card.ID() {
 					ctx.ScheduleAfter(func() {
 						for _, c := range ctx.Match().Opponent(card.Player()).CollectCards(match.BATTLEZONE) {
-							c.Tapped = true
+							c.SetTapped(true)
 						}
 					})
 				}
-			},
+			}, &match.SpellCast{}),
 		},
 	}
 
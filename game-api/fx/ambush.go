@@ -6,7 +6,15 @@ import (
 
 // Ambush ...
 func Ambush(card *match.Card, ctx *match.Context) {
-	if event, ok := ctx.Event().(*match.TrapEvent); ok && event.ID == card.ID() {
+	// Ambush only answers attacks
+	if event, ok := ctx.Event().(*match.TrapEvent); ok && event.ID == card.ID() && event.Attacker != nil {
+		event.Activated = true
+
+		if ctx.DryRun() && !ctx.Match().DryRun(&match.PlayCardEvent{ID: card.ID()}) {
+			ctx.InterruptFlow()
+			return
+		}
+
 		// Do this last in case any other cards want to interrupt the flow
 		ctx.ScheduleAfter(func() {
 			playCtx := match.NewContext(ctx.Match(), &match.PlayCardEvent{
@@ -24,7 +32,7 @@ func Ambush(card *match.Card, ctx *match.Context) {
 			}
 
 			ctx.Match().Battle(card, event.Attacker, false)
-			card.Tapped = true
+			card.SetTapped(true)
 		})
 	}
 }
@@ -9,7 +9,10 @@ import (
 // CantBeAttacked ...
 func CantBeAttacked(card *match.Card, ctx *match.Context) {
 	if event, ok := ctx.Event().(*match.AttackCreature); ok && event.TargetID == card.ID() {
-		ctx.Match().WarnPlayer(ctx.Match().Opponent(card.Player()), fmt.Sprintf("Can't attack %s", card.Name()))
+		if !ctx.DryRun() {
+			ctx.Match().WarnPlayer(ctx.Match().Opponent(card.Player()), fmt.Sprintf("Can't attack %s", card.Name()))
+		}
+
 		ctx.InterruptFlow()
 	}
 }
@@ -9,7 +9,10 @@ import (
 // CantBeBlocked ...
 func CantBeBlocked(card *match.Card, ctx *match.Context) {
 	if event, ok := ctx.Event().(*match.BlockEvent); ok && event.Attacker == card {
-		ctx.Match().WarnPlayer(ctx.Match().Opponent(card.Player()), fmt.Sprintf("Can't block %s", card.Name()))
+		if !ctx.DryRun() {
+			ctx.Match().WarnPlayer(ctx.Match().Opponent(card.Player()), fmt.Sprintf("Can't block %s", card.Name()))
+		}
+
 		ctx.InterruptFlow()
 	}
 }
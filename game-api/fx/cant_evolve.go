@@ -9,7 +9,10 @@ import (
 // CantEvolve ...
 func CantEvolve(card *match.Card, ctx *match.Context) {
 	if event, ok := ctx.Event().(*match.Evolve); ok && event.Target == card {
-		ctx.Match().WarnPlayer(card.Player(), fmt.Sprintf("Can't evolve %s", card.Name()))
+		if !ctx.DryRun() {
+			ctx.Match().WarnPlayer(card.Player(), fmt.Sprintf("Can't evolve %s", card.Name()))
+		}
+
 		ctx.InterruptFlow()
 	}
 }
@@ -0,0 +1,11 @@
+package fx
+
+import "github.com/jyotiskaghosh/ganjifa/game-api/match"
+
+// The handlers cards are given as conditions, registered so they can be snapshotted
+func init() {
+	match.RegisterCondition("fx.CantBeBlocked", CantBeBlocked)
+	match.RegisterCondition("fx.CantEvolve", CantEvolve)
+	match.RegisterCondition("fx.DestroyEndOfTurn", DestroyEndOfTurn)
+	match.RegisterCondition("fx.Leech", Leech)
+}
@@ -16,8 +16,7 @@ func Creature(card *match.Card, ctx *match.Context) {
 	// Untap the card
 	case *match.UntapStep:
 		if card.Player().IsPlayerTurn() {
-			card.ClearConditions()
-			card.Tapped = false
+			card.SetTapped(false)
 		}
 	// On playing the card
 	case *match.PlayCardEvent:
@@ -31,18 +30,27 @@ func Creature(card *match.Card, ctx *match.Context) {
 					}
 					ctx.Match().Chat("server", fmt.Sprintf("%s summoned creature %s", card.Player().Name(), card.Name()))
 
-					card.AddCondition(CantEvolve)
+					card.AddCondition(match.UntilYourNextTurn, CantEvolve)
 				})
 			} else {
+				targets := match.Filter(
+					card.Player().CollectCards(match.BATTLEZONE),
+					func(c *match.Card) bool {
+						return c.HasFamily(card.Family(), ctx) &&
+							card.GetRank(ctx)-c.GetRank(ctx) == 1 &&
+							ctx.Match().DryRun(&match.Evolve{ID: card.ID(), Target: c})
+					},
+				)
+
+				if len(targets) < 1 {
+					ctx.InterruptFlow()
+					return
+				}
+
 				// Do this last in case any other cards want to interrupt the flow
 				ctx.ScheduleAfter(func() {
 					cards := card.Player().Search(
-						match.Filter(
-							card.Player().CollectCards(match.BATTLEZONE),
-							func(c *match.Card) bool {
-								return c.HasFamily(card.Family(), ctx) && card.GetRank(ctx)-c.GetRank(ctx) == 1
-							},
-						),
+						targets,
 						fmt.Sprintf("choose a creature to evolve %s", card.Name()),
 						1,
 						1,
@@ -71,7 +79,7 @@ func Creature(card *match.Card, ctx *match.Context) {
 			ctx.ScheduleAfter(func() {
 				event.Target.EvolveTo(card)
 				ctx.Match().Chat("server", fmt.Sprintf("%s evolved %s to %s", card.Player().Name(), event.Target.Name(), card.Name()))
-				card.AddCondition(CantEvolve)
+				card.AddCondition(match.UntilYourNextTurn, CantEvolve)
 			})
 		}
 	// When attacking player
@@ -83,93 +91,23 @@ func Creature(card *match.Card, ctx *match.Context) {
 			}
 
 			ctx.ScheduleAfter(func() {
-				opponent.Action = make(chan []string)
-				opponent.Cancel = make(chan bool)
-
-				defer close(opponent.Cancel)
-				defer close(opponent.Action)
-
-				text := fmt.Sprintf("%s is attacking %s, you may play a set down card or block with a creature", card.Name(), opponent.Name())
-				cards := match.Filter(opponent.CollectCards(match.BATTLEZONE, match.TRAPZONE), func(c *match.Card) bool { return !c.Tapped })
-
-				if len(cards) < 1 {
-					opponent.Damage(card, ctx, card.GetAttack(ctx))
-					return
-				}
-
-				ctx.Match().NewAction(opponent, cards, 1, 1, text, true)
-				defer ctx.Match().CloseAction(opponent)
-
-				for {
-					select {
-					case action := <-opponent.Action:
-						{
-							if len(action) < 1 || len(action) > 1 || !match.AssertCardsIn(cards, action...) {
-								ctx.Match().WarnPlayer(opponent, "The cards you selected does not meet the requirements")
-								continue
-							}
-
-							for _, id := range action {
-								c, err := match.GetCard(
-									id,
-									opponent.CollectCards(match.BATTLEZONE, match.TRAPZONE))
-								if err != nil {
-									logrus.Debugf("Search: %s", err)
-									continue
-								}
-
-								if c.Zone() == match.TRAPZONE {
-									ctx.Match().HandleFx(match.NewContext(ctx.Match(), &match.TrapEvent{
-										ID:       c.ID(),
-										Attacker: card,
-									}))
-								} else {
-									// Blocking attack
-									blockCtx := match.NewContext(ctx.Match(), &match.BlockEvent{
-										ID:       c.ID(),
-										Attacker: card,
-									})
-									ctx.Match().HandleFx(blockCtx)
-
-									if !blockCtx.Cancelled() {
-										ctx.InterruptFlow()
-										return
-									}
-								}
-
-								// Update card
-								card, err = match.GetCard(event.ID, card.Player().CollectCards(match.BATTLEZONE))
-								if err != nil {
-									logrus.Debug(err)
-									ctx.InterruptFlow()
-									return
-								}
-
-								if card.Tapped || card.Zone() != match.BATTLEZONE || card.GetAttack(ctx) <= 0 {
-									ctx.InterruptFlow()
-									return
-								}
+				attacker, ok := defend(ctx, opponent, func() (*match.Card, string, bool) {
+					// Update card
+					card, err := match.GetCard(event.ID, card.Player().CollectCards(match.BATTLEZONE))
+					if err != nil {
+						logrus.Debug(err)
+						return nil, "", false
+					}
 
-								text := fmt.Sprintf("%s is attacking %s, you may play a set down card or block with a creature", card.Name(), opponent.Name())
-								cards = match.Filter(opponent.CollectCards(match.BATTLEZONE, match.TRAPZONE), func(c *match.Card) bool { return !c.Tapped })
+					if card.Tapped || card.Zone() != match.BATTLEZONE || card.GetAttack(ctx) <= 0 {
+						return nil, "", false
+					}
 
-								if len(cards) < 1 {
-									opponent.Damage(card, ctx, card.GetAttack(ctx))
-									return
-								}
+					return card, fmt.Sprintf("%s is attacking %s, you may play a set down card or block with a creature", card.Name(), opponent.Name()), true
+				})
 
-								// refreshes the action
-								ctx.Match().NewAction(opponent, cards, 1, 1, text, true)
-								opponent.Action = make(chan []string)
-								opponent.Cancel = make(chan bool)
-							}
-						}
-					case cancel := <-opponent.Cancel:
-						if cancel {
-							opponent.Damage(card, ctx, card.GetAttack(ctx))
-							return
-						}
-					}
+				if ok {
+					opponent.Damage(attacker, ctx, attacker.GetAttack(ctx))
 				}
 			})
 		}
@@ -195,107 +133,36 @@ func Creature(card *match.Card, ctx *match.Context) {
 			}
 
 			ctx.ScheduleAfter(func() {
-				opponent.Action = make(chan []string)
-				opponent.Cancel = make(chan bool)
-
-				defer close(opponent.Cancel)
-				defer close(opponent.Action)
-
-				text := fmt.Sprintf("%s is attacking %s, you may play a set down card or block with a creature", card.Name(), target.Name())
-				cards := match.Filter(opponent.CollectCards(match.BATTLEZONE, match.TRAPZONE), func(c *match.Card) bool { return !c.Tapped })
-
-				if len(cards) < 1 {
-					ctx.Match().Battle(card, target, false)
-					return
-				}
-
-				ctx.Match().NewAction(opponent, cards, 1, 1, text, true)
-				defer ctx.Match().CloseAction(opponent)
-
-				for {
-					select {
-					case action := <-opponent.Action:
-						{
-							if len(action) < 1 || len(action) > 1 || !match.AssertCardsIn(cards, action...) {
-								ctx.Match().WarnPlayer(opponent, "The cards you selected does not meet the requirements")
-								continue
-							}
-
-							for _, id := range action {
-								c, err := match.GetCard(
-									id,
-									opponent.CollectCards(match.BATTLEZONE, match.TRAPZONE))
-								if err != nil {
-									logrus.Debugf("Search: %s", err)
-									continue
-								}
-
-								if c.Zone() == match.TRAPZONE {
-									ctx.Match().HandleFx(match.NewContext(ctx.Match(), &match.TrapEvent{
-										ID:       c.ID(),
-										Attacker: card,
-									}))
-								} else {
-									// Blocking attack
-									blockCtx := match.NewContext(ctx.Match(), &match.BlockEvent{
-										ID:       c.ID(),
-										Attacker: card,
-									})
-									ctx.Match().HandleFx(blockCtx)
-
-									if !blockCtx.Cancelled() {
-										ctx.InterruptFlow()
-										return
-									}
-								}
-
-								// Update card
-								card, err = match.GetCard(event.ID, card.Player().CollectCards(match.BATTLEZONE))
-								if err != nil {
-									logrus.Debug(err)
-									ctx.InterruptFlow()
-									return
-								}
-
-								// Update target
-								target, err := match.GetCard(
-									event.TargetID,
-									match.Filter(opponent.CollectCards(match.BATTLEZONE), func(c *match.Card) bool { return c.Tapped }),
-								)
-								if err != nil {
-									logrus.Debug(err)
-									ctx.InterruptFlow()
-									return
-								}
+				attacker, ok := defend(ctx, opponent, func() (*match.Card, string, bool) {
+					// Update card
+					card, err := match.GetCard(event.ID, card.Player().CollectCards(match.BATTLEZONE))
+					if err != nil {
+						logrus.Debug(err)
+						return nil, "", false
+					}
 
-								if card.Tapped ||
-									card.Zone() != match.BATTLEZONE ||
-									card.GetAttack(ctx) <= 0 ||
-									target.Zone() != match.BATTLEZONE {
-									ctx.InterruptFlow()
-									return
-								}
+					// Update target
+					target, err = match.GetCard(
+						event.TargetID,
+						match.Filter(opponent.CollectCards(match.BATTLEZONE), func(c *match.Card) bool { return c.Tapped }),
+					)
+					if err != nil {
+						logrus.Debug(err)
+						return nil, "", false
+					}
 
-								text = fmt.Sprintf("%s is attacking %s, you may play a set down card or block with a creature", card.Name(), target.Name())
-								cards = match.Filter(opponent.CollectCards(match.BATTLEZONE, match.TRAPZONE), func(c *match.Card) bool { return !c.Tapped })
+					if card.Tapped ||
+						card.Zone() != match.BATTLEZONE ||
+						card.GetAttack(ctx) <= 0 ||
+						target.Zone() != match.BATTLEZONE {
+						return nil, "", false
+					}
 
-								if len(cards) < 1 {
-									ctx.Match().Battle(card, target, false)
-									return
-								}
+					return card, fmt.Sprintf("%s is attacking %s, you may play a set down card or block with a creature", card.Name(), target.Name()), true
+				})
 
-								// refreshes the action
-								ctx.Match().NewAction(opponent, cards, 1, 1, text, true)
-								opponent.Action = make(chan []string)
-								opponent.Cancel = make(chan bool)
-							}
-						}
-					case cancel := <-opponent.Cancel:
-						if cancel {
-							ctx.Match().Battle(card, target, false)
-							return
-						}
-					}
+				if ok {
+					ctx.Match().Battle(attacker, target, false)
 				}
 			})
 		}
@@ -309,7 +176,7 @@ func Creature(card *match.Card, ctx *match.Context) {
 
 			// Do this last in case any other cards want to interrupt the flow
 			ctx.ScheduleAfter(func() {
-				card.Tapped = true
+				card.SetTapped(true)
 				ctx.Match().Battle(event.Attacker, card, true)
 			})
 		}
@@ -330,3 +197,53 @@ func Creature(card *match.Card, ctx *match.Context) {
 		}
 	}
 }
+
+// defend lets the defending player play set down cards or block with a creature until they block or pass.
+// attack refreshes the attacker after every response and describes the attack, or returns false if the attack fizzled.
+// It returns the attacker and true if the attack went through unanswered
+func defend(ctx *match.Context, defender *match.Player, attack func() (*match.Card, string, bool)) (*match.Card, bool) {
+	for {
+		attacker, text, ok := attack()
+		if !ok {
+			ctx.InterruptFlow()
+			return nil, false
+		}
+
+		cards := match.Filter(defender.CollectCards(match.BATTLEZONE), func(c *match.Card) bool { return !c.Tapped })
+		cards = append(cards, match.Filter(defender.CollectCards(match.TRAPZONE), func(c *match.Card) bool {
+			return ctx.Match().CanActivate(c, attacker)
+		})...)
+
+		selected, cancelled := defender.Prompt(match.Prompt{
+			Cards:       cards,
+			Text:        text,
+			Min:         1,
+			Max:         1,
+			Cancellable: true,
+			Source:      attacker,
+		})
+
+		if cancelled || len(selected) < 1 {
+			return attacker, true
+		}
+
+		c := selected[0]
+
+		if c.Zone() == match.TRAPZONE {
+			ctx.Match().ActivateTrap(c, attacker)
+			continue
+		}
+
+		// Blocking attack
+		blockCtx := match.NewContext(ctx.Match(), &match.BlockEvent{
+			ID:       c.ID(),
+			Attacker: attacker,
+		})
+		ctx.Match().HandleFx(blockCtx)
+
+		if !blockCtx.Cancelled() {
+			ctx.InterruptFlow()
+			return nil, false
+		}
+	}
+}
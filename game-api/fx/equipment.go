@@ -14,15 +14,22 @@ func Equipment(card *match.Card, ctx *match.Context) {
 	// On card played
 	case *match.PlayCardEvent:
 		if event.ID == card.ID() {
+			targets := match.Filter(
+				card.Player().CollectCards(match.BATTLEZONE),
+				func(c *match.Card) bool {
+					return c.HasCivilisation(card.Civ(), ctx) && card.GetRank(ctx) <= c.GetRank(ctx)
+				},
+			)
+
+			if len(targets) < 1 {
+				ctx.InterruptFlow()
+				return
+			}
+
 			// Do this last in case any other cards want to interrupt the flow
 			ctx.ScheduleAfter(func() {
 				cards := card.Player().Search(
-					match.Filter(
-						card.Player().CollectCards(match.BATTLEZONE),
-						func(c *match.Card) bool {
-							return c.HasCivilisation(card.Civ(), ctx) && card.GetRank(ctx) <= c.GetRank(ctx)
-						},
-					),
+					targets,
 					fmt.Sprintf("choose a creature to equip %s", card.Name()),
 					1,
 					1,
@@ -52,7 +59,15 @@ func Equipment(card *match.Card, ctx *match.Context) {
 		}
 	// When the equipment is played reactively
 	case *match.TrapEvent:
-		if event.ID == card.ID() {
+		// Equipment only answers attacks
+		if event.ID == card.ID() && event.Attacker != nil {
+			event.Activated = true
+
+			if ctx.DryRun() && !ctx.Match().DryRun(&match.PlayCardEvent{ID: card.ID()}) {
+				ctx.InterruptFlow()
+				return
+			}
+
 			// Do this last in case any other cards want to interrupt the flow
 			ctx.ScheduleAfter(func() {
 				playCtx := match.NewContext(ctx.Match(), &match.PlayCardEvent{
@@ -66,15 +81,11 @@ func Equipment(card *match.Card, ctx *match.Context) {
 				}
 			})
 		}
-	// When calculating attack
-	case *match.GetAttackEvent:
-		if card.AttachedTo() != nil && event.ID == card.AttachedTo().ID() {
-			event.Attack += card.Attack()
-		}
-	// When calculating defence
-	case *match.GetDefenceEvent:
-		if card.AttachedTo() != nil && event.ID == card.AttachedTo().ID() {
-			event.Defence += card.Defence()
+	// When calculating attack and defence
+	case *match.ContinuousEvent:
+		if event.Layer == match.ModifierLayer && card.AttachedTo() != nil && event.ID == card.AttachedTo().ID() {
+			event.Stats.Attack += int(card.Attack())
+			event.Stats.Defence += int(card.Defence())
 		}
 	}
 }
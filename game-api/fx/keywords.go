@@ -0,0 +1,13 @@
+package fx
+
+import "github.com/jyotiskaghosh/ganjifa/game-api/match"
+
+// The handlers that are keywords, registered so clients can show them
+func init() {
+	match.RegisterKeyword("Ambush", Ambush)
+	match.RegisterKeyword("CantBeAttacked", CantBeAttacked)
+	match.RegisterKeyword("CantBeBlocked", CantBeBlocked)
+	match.RegisterKeyword("Leech", Leech)
+	match.RegisterKeyword("Poisonous", Poisonous)
+	match.RegisterKeyword("Venomous", Venomous)
+}
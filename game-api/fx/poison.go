@@ -10,7 +10,7 @@ import (
 func Poisonous(card *match.Card, ctx *match.Context) {
 	if event, ok := ctx.Event().(*match.Battle); ok && event.Defender == card {
 		ctx.ScheduleAfter(func() {
-			event.Attacker.AddCondition(DestroyEndOfTurn)
+			event.Attacker.AddCondition(match.UntilEndOfTurn, DestroyEndOfTurn)
 			ctx.Match().Chat("server", fmt.Sprintf("%s is poisoned by %s", event.Attacker.Name(), card.Name()))
 		})
 	}
@@ -20,7 +20,7 @@ func Poisonous(card *match.Card, ctx *match.Context) {
 func Venomous(card *match.Card, ctx *match.Context) {
 	if event, ok := ctx.Event().(*match.Battle); ok && event.Attacker == card {
 		ctx.ScheduleAfter(func() {
-			event.Defender.AddCondition(DestroyEndOfTurn)
+			event.Defender.AddCondition(match.UntilEndOfTurn, DestroyEndOfTurn)
 			ctx.Match().Chat("server", fmt.Sprintf("%s is poisoned by %s", event.Attacker.Name(), card.Name()))
 		})
 	}
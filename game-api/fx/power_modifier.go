@@ -2,16 +2,16 @@ package fx
 
 import "github.com/jyotiskaghosh/ganjifa/game-api/match"
 
-// AttackModifier ...
+// AttackModifier adds n to the attack of card as a continuous effect
 func AttackModifier(card *match.Card, ctx *match.Context, n uint8) {
-	if event, ok := ctx.Event().(*match.GetAttackEvent); ok && event.ID == card.ID() {
-		event.Attack += n
+	if event, ok := ctx.Event().(*match.ContinuousEvent); ok && event.Layer == match.ModifierLayer && event.ID == card.ID() {
+		event.Stats.Attack += int(n)
 	}
 }
 
-// DefenceModifier ...
+// DefenceModifier adds n to the defence of card as a continuous effect
 func DefenceModifier(card *match.Card, ctx *match.Context, n uint8) {
-	if event, ok := ctx.Event().(*match.GetDefenceEvent); ok && event.ID == card.ID() {
-		event.Defence += n
+	if event, ok := ctx.Event().(*match.ContinuousEvent); ok && event.Layer == match.ModifierLayer && event.ID == card.ID() {
+		event.Stats.Defence += int(n)
 	}
 }
@@ -15,6 +15,13 @@ func Spell(card *match.Card, ctx *match.Context) {
 	// When the spell is played reactively
 	case *match.TrapEvent:
 		if event.ID == card.ID() {
+			event.Activated = true
+
+			if ctx.DryRun() && !ctx.Match().DryRun(&match.PlayCardEvent{ID: card.ID()}) {
+				ctx.InterruptFlow()
+				return
+			}
+
 			// Do this last in case any other cards want to interrupt the flow
 			ctx.ScheduleAfter(func() {
 				playCtx := match.NewContext(ctx.Match(), &match.PlayCardEvent{
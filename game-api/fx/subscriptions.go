@@ -0,0 +1,31 @@
+package fx
+
+import "github.com/jyotiskaghosh/ganjifa/game-api/match"
+
+// The events each handler handles, it is only called for these
+func init() {
+	match.Subscribe(Creature,
+		&match.UntapStep{},
+		&match.PlayCardEvent{},
+		&match.Evolve{},
+		&match.AttackPlayer{},
+		&match.AttackCreature{},
+		&match.BlockEvent{},
+		&match.CreatureDestroyed{},
+	)
+	match.Subscribe(Equipment,
+		&match.PlayCardEvent{},
+		&match.Equip{},
+		&match.TrapEvent{},
+		&match.ContinuousEvent{},
+	)
+	match.Subscribe(Spell, &match.PlayCardEvent{}, &match.TrapEvent{})
+	match.Subscribe(Ambush, &match.TrapEvent{})
+	match.Subscribe(CantBeAttacked, &match.AttackCreature{})
+	match.Subscribe(CantBeBlocked, &match.BlockEvent{})
+	match.Subscribe(CantEvolve, &match.Evolve{})
+	match.Subscribe(DestroyEndOfTurn, &match.EndStep{})
+	match.Subscribe(Leech, &match.DamageEvent{}, &match.CreatureDestroyed{})
+	match.Subscribe(Poisonous, &match.Battle{})
+	match.Subscribe(Venomous, &match.Battle{})
+}
@@ -0,0 +1,218 @@
+package fx
+
+import (
+	"fmt"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/jyotiskaghosh/ganjifa/game-api/match"
+)
+
+// Params are the parameters of an effect template, as they are decoded from a card definition
+type Params map[string]interface{}
+
+// Int returns the parameter with the given name as a whole number
+func (p Params) Int(name string) (int, error) {
+	v, ok := p[name].(float64)
+	if !ok || v != float64(int(v)) {
+		return 0, fmt.Errorf("parameter %s must be a whole number", name)
+	}
+
+	return int(v), nil
+}
+
+// Uint8 returns the parameter with the given name as a whole number from 0 to 255
+func (p Params) Uint8(name string) (uint8, error) {
+	v, err := p.Int(name)
+	if err != nil {
+		return 0, err
+	}
+
+	if v < 0 || v > 255 {
+		return 0, fmt.Errorf("parameter %s must be between 0 and 255", name)
+	}
+
+	return uint8(v), nil
+}
+
+// String returns the parameter with the given name as a string
+func (p Params) String(name string) (string, error) {
+	v, ok := p[name].(string)
+	if !ok || v == "" {
+		return "", fmt.Errorf("parameter %s must be a string", name)
+	}
+
+	return v, nil
+}
+
+// Template builds a handler from the parameters given by a card definition
+type Template func(params Params) (match.HandlerFunc, error)
+
+var templates = make(map[string]Template)
+
+// RegisterTemplate makes a template known by name to card definitions. It should be called from init functions
+func RegisterTemplate(name string, template Template) {
+	templates[name] = template
+}
+
+// Effect returns the handler built by the template with the given name
+func Effect(name string, params Params) (match.HandlerFunc, error) {
+	template, ok := templates[name]
+	if !ok {
+		return nil, fmt.Errorf("effect %s does not exist", name)
+	}
+
+	handler, err := template(params)
+	if err != nil {
+		return nil, fmt.Errorf("effect %s: %s", name, err)
+	}
+
+	return handler, nil
+}
+
+// keyword returns a template for a handler without parameters
+func keyword(handler match.HandlerFunc) Template {
+	return func(params Params) (match.HandlerFunc, error) {
+		return handler, nil
+	}
+}
+
+func init() {
+	RegisterTemplate("Creature", keyword(Creature))
+	RegisterTemplate("Equipment", keyword(Equipment))
+	RegisterTemplate("Spell", keyword(Spell))
+	RegisterTemplate("Ambush", keyword(Ambush))
+	RegisterTemplate("CantBeAttacked", keyword(CantBeAttacked))
+	RegisterTemplate("CantBeBlocked", keyword(CantBeBlocked))
+	RegisterTemplate("Leech", keyword(Leech))
+	RegisterTemplate("Poisonous", keyword(Poisonous))
+	RegisterTemplate("Venomous", keyword(Venomous))
+
+	RegisterTemplate("AttackBonus", attackBonus)
+	RegisterTemplate("DefenceBonus", defenceBonus)
+	RegisterTemplate("FamilyBonus", familyBonus)
+	RegisterTemplate("SearchOnEnter", searchOnEnter)
+	RegisterTemplate("DrawCards", drawCards)
+	RegisterTemplate("Heal", heal)
+}
+
+// attackBonus adds amount to the attack of the card
+func attackBonus(params Params) (match.HandlerFunc, error) {
+	amount, err := params.Uint8("amount")
+	if err != nil {
+		return nil, err
+	}
+
+	return match.Subscribe(func(card *match.Card, ctx *match.Context) {
+		AttackModifier(card, ctx, amount)
+	}, &match.ContinuousEvent{}), nil
+}
+
+// defenceBonus adds amount to the defence of the card
+func defenceBonus(params Params) (match.HandlerFunc, error) {
+	amount, err := params.Uint8("amount")
+	if err != nil {
+		return nil, err
+	}
+
+	return match.Subscribe(func(card *match.Card, ctx *match.Context) {
+		DefenceModifier(card, ctx, amount)
+	}, &match.ContinuousEvent{}), nil
+}
+
+// familyBonus adds amount to the attack and defence of the card in play for each other creature of the family
+// in its battlezone, counting the families creatures have through effects
+func familyBonus(params Params) (match.HandlerFunc, error) {
+	family, err := params.String("family")
+	if err != nil {
+		return nil, err
+	}
+
+	amount, err := params.Uint8("amount")
+	if err != nil {
+		return nil, err
+	}
+
+	return match.Subscribe(func(card *match.Card, ctx *match.Context) {
+		// The families of the other creatures are only looked up for the card's own modifiers
+		event, ok := ctx.Event().(*match.ContinuousEvent)
+		if !ok || event.Layer != match.ModifierLayer || event.ID != card.ID() || card.Zone() != match.BATTLEZONE {
+			return
+		}
+
+		for _, c := range card.Player().CollectCards(match.BATTLEZONE) {
+			if c != card && c.HasFamily(family, ctx) {
+				AttackModifier(card, ctx, amount)
+				DefenceModifier(card, ctx, amount)
+			}
+		}
+	}, &match.ContinuousEvent{}), nil
+}
+
+// searchOnEnter lets the player search their deck for a card of the family when the card enters their battlezone
+func searchOnEnter(params Params) (match.HandlerFunc, error) {
+	family, err := params.String("family")
+	if err != nil {
+		return nil, err
+	}
+
+	return OnEnterBattlezone(func(card *match.Card, ctx *match.Context) {
+		ctx.Match().Push(card, fmt.Sprintf("%s searches %s's deck for a %s", card.Name(), card.Player().Name(), family), func() {
+			cards, err := card.Player().Container(match.DECK)
+			if err != nil {
+				logrus.Debug(err)
+				return
+			}
+
+			cards = card.Player().Search(
+				match.Filter(cards, func(x *match.Card) bool { return x.Family() == family }),
+				fmt.Sprintf("Select 1 %s", family),
+				1,
+				1,
+				true)
+
+			for _, c := range cards {
+				if err := c.MoveCard(match.HAND); err != nil {
+					logrus.Debug(err)
+					return
+				}
+
+				ctx.Match().Chat("Server", fmt.Sprintf("%s was moved from %s's deck to their hand", c.Name(), card.Player().Name()))
+			}
+
+			card.Player().ShuffleDeck()
+		})
+	}), nil
+}
+
+// drawCards makes the player draw count cards when the spell is cast
+func drawCards(params Params) (match.HandlerFunc, error) {
+	count, err := params.Int("count")
+	if err != nil {
+		return nil, err
+	}
+
+	return match.Subscribe(func(card *match.Card, ctx *match.Context) {
+		if event, ok := ctx.Event().(*match.SpellCast); ok && event.ID == card.ID() {
+			ctx.ScheduleAfter(func() {
+				card.Player().DrawCards(count)
+			})
+		}
+	}, &match.SpellCast{}), nil
+}
+
+// heal gives the player amount life when the spell is cast
+func heal(params Params) (match.HandlerFunc, error) {
+	amount, err := params.Uint8("amount")
+	if err != nil {
+		return nil, err
+	}
+
+	return match.Subscribe(func(card *match.Card, ctx *match.Context) {
+		if event, ok := ctx.Event().(*match.SpellCast); ok && event.ID == card.ID() {
+			ctx.ScheduleAfter(func() {
+				card.Player().Heal(card, ctx, amount)
+			})
+		}
+	}, &match.SpellCast{}), nil
+}
@@ -0,0 +1,116 @@
+package fx
+
+import (
+	"github.com/jyotiskaghosh/ganjifa/game-api/match"
+)
+
+// trigger runs fn for the card once the event is handled, unless the event was interrupted,
+// and once the effects being handled are over
+func trigger(card *match.Card, ctx *match.Context, fn match.HandlerFunc) {
+	ctx.ScheduleAfter(func() {
+		ctx.Match().Trigger(func() {
+			fn(card, ctx)
+		})
+	})
+}
+
+// OnEnterBattlezone triggers fn when the card enters its battlezone, either from another zone or as a token.
+// Moving back from under an evolution doesn't count
+func OnEnterBattlezone(fn match.HandlerFunc) match.HandlerFunc {
+	return match.Subscribe(func(card *match.Card, ctx *match.Context) {
+		switch event := ctx.Event().(type) {
+		case *match.CardMoved:
+			if event.ID == card.ID() && event.To == match.BATTLEZONE &&
+				event.From != match.BATTLEZONE && event.From != match.SOUL {
+				trigger(card, ctx, fn)
+			}
+		case *match.TokenCreated:
+			if event.ID == card.ID() {
+				trigger(card, ctx, fn)
+			}
+		}
+	}, &match.CardMoved{}, &match.TokenCreated{})
+}
+
+// OnLeaveBattlezone triggers fn when the card leaves its battlezone, including tokens that vanish.
+// Going under an evolution doesn't count
+func OnLeaveBattlezone(fn match.HandlerFunc) match.HandlerFunc {
+	return match.Subscribe(func(card *match.Card, ctx *match.Context) {
+		switch event := ctx.Event().(type) {
+		case *match.CardMoved:
+			if event.ID == card.ID() && event.From == match.BATTLEZONE &&
+				event.To != match.BATTLEZONE && event.To != match.SOUL {
+				trigger(card, ctx, fn)
+			}
+		case *match.TokenVanished:
+			if event.ID == card.ID() && event.From == match.BATTLEZONE {
+				trigger(card, ctx, fn)
+			}
+		}
+	}, &match.CardMoved{}, &match.TokenVanished{})
+}
+
+// OnDestroyed triggers fn when the card is destroyed in its battlezone
+func OnDestroyed(fn match.HandlerFunc) match.HandlerFunc {
+	return match.Subscribe(func(card *match.Card, ctx *match.Context) {
+		if event, ok := ctx.Event().(*match.CreatureDestroyed); ok &&
+			event.ID == card.ID() && card.Zone() == match.BATTLEZONE {
+			trigger(card, ctx, fn)
+		}
+	}, &match.CreatureDestroyed{})
+}
+
+// OnAttack triggers fn when the card attacks a player or a creature, once the attack is over
+func OnAttack(fn match.HandlerFunc) match.HandlerFunc {
+	return match.Subscribe(func(card *match.Card, ctx *match.Context) {
+		switch event := ctx.Event().(type) {
+		case *match.AttackPlayer:
+			if event.ID == card.ID() && card.Zone() == match.BATTLEZONE {
+				trigger(card, ctx, fn)
+			}
+		case *match.AttackCreature:
+			if event.ID == card.ID() && card.Zone() == match.BATTLEZONE {
+				trigger(card, ctx, fn)
+			}
+		}
+	}, &match.AttackPlayer{}, &match.AttackCreature{})
+}
+
+// OnBlock triggers fn when the card blocks an attack, once the battle is over
+func OnBlock(fn match.HandlerFunc) match.HandlerFunc {
+	return match.Subscribe(func(card *match.Card, ctx *match.Context) {
+		if event, ok := ctx.Event().(*match.BlockEvent); ok &&
+			event.ID == card.ID() && card.Zone() == match.BATTLEZONE {
+			trigger(card, ctx, fn)
+		}
+	}, &match.BlockEvent{})
+}
+
+// AtStartOfYourTurn triggers fn at the start of each turn of the card's owner while it is in their battlezone
+func AtStartOfYourTurn(fn match.HandlerFunc) match.HandlerFunc {
+	return match.Subscribe(func(card *match.Card, ctx *match.Context) {
+		if _, ok := ctx.Event().(*match.StartOfTurnStep); ok &&
+			card.Player().IsPlayerTurn() && card.Zone() == match.BATTLEZONE {
+			trigger(card, ctx, fn)
+		}
+	}, &match.StartOfTurnStep{})
+}
+
+// AtEndOfYourTurn triggers fn at the end of each turn of the card's owner while it is in their battlezone
+func AtEndOfYourTurn(fn match.HandlerFunc) match.HandlerFunc {
+	return match.Subscribe(func(card *match.Card, ctx *match.Context) {
+		if _, ok := ctx.Event().(*match.EndStep); ok &&
+			card.Player().IsPlayerTurn() && card.Zone() == match.BATTLEZONE {
+			trigger(card, ctx, fn)
+		}
+	}, &match.EndStep{})
+}
+
+// OnDamageDealt triggers fn when the card deals damage to a player, the event holds how much
+func OnDamageDealt(fn match.HandlerFunc) match.HandlerFunc {
+	return match.Subscribe(func(card *match.Card, ctx *match.Context) {
+		if event, ok := ctx.Event().(*match.DamageEvent); ok && event.Source == card {
+			trigger(card, ctx, fn)
+		}
+	}, &match.DamageEvent{})
+}
@@ -8,7 +8,6 @@ import (
 	"github.com/jyotiskaghosh/ganjifa/game-api/family"
 
 	"github.com/sirupsen/logrus"
-	"github.com/ventu-io/go-shortid"
 )
 
 // Card stores card data
@@ -17,6 +16,7 @@ type Card struct {
 
 	cardID  int
 	name    string
+	text    string
 	rank    uint8
 	civ     civ.Civilisation
 	family  string
@@ -25,9 +25,11 @@ type Card struct {
 	effects []HandlerFunc
 
 	zone       Container
-	Tapped     bool
+	Tapped     bool // Changed through SetTapped
 	attachedTo *Card
-	conditions []HandlerFunc // Temporary effects
+	conditions []condition // Temporary effects
+	counters   map[string]int
+	token      bool
 
 	player *Player
 }
@@ -35,6 +37,7 @@ type Card struct {
 // CardBuilder is a builder for Card
 type CardBuilder struct {
 	Name    string
+	Text    string
 	Rank    uint8
 	Civ     civ.Civilisation
 	Family  string
@@ -47,6 +50,7 @@ type CardBuilder struct {
 func (cb *CardBuilder) Build() *Card {
 	return &Card{
 		name:    cb.Name,
+		text:    cb.Text,
 		rank:    cb.Rank,
 		civ:     cb.Civ,
 		family:  cb.Family,
@@ -64,12 +68,7 @@ func NewCard(p *Player, cardID int) (*Card, error) {
 		return nil, err
 	}
 
-	id, err := shortid.Generate()
-	if err != nil {
-		return nil, err
-	}
-
-	c.id = id
+	c.id = p.match.newID()
 	c.cardID = cardID
 	c.player = p
 	c.zone = DECK
@@ -92,6 +91,11 @@ func (c *Card) Name() string {
 	return c.name
 }
 
+// Text returns the rules text of the card
+func (c *Card) Text() string {
+	return c.text
+}
+
 // Rank ...
 func (c *Card) Rank() uint8 {
 	return c.rank
@@ -127,27 +131,61 @@ func (c *Card) Player() *Player {
 	return c.player
 }
 
-// AddCondition adds temporary handler functions
-func (c *Card) AddCondition(effects ...HandlerFunc) {
-	c.conditions = append(c.conditions, effects...)
+// AddCondition adds temporary handler functions that last for the given duration
+func (c *Card) AddCondition(duration Duration, effects ...HandlerFunc) {
+	for _, effect := range effects {
+		c.conditions = append(c.conditions, condition{handler: effect, duration: duration})
+	}
+	c.invalidate()
 }
 
 // RemoveCondition removes all instances of the given handler from the cards conditions
 func (c *Card) RemoveCondition(handler HandlerFunc) {
-	tmp := make([]HandlerFunc, 0)
+	tmp := make([]condition, 0)
 
 	for _, condition := range c.conditions {
-		if reflect.ValueOf(condition).Pointer() != reflect.ValueOf(handler).Pointer() {
+		if reflect.ValueOf(condition.handler).Pointer() != reflect.ValueOf(handler).Pointer() {
 			tmp = append(tmp, condition)
 		}
 	}
 
 	c.conditions = tmp
+	c.invalidate()
 }
 
 // ClearConditions removes all conditions from the card
 func (c *Card) ClearConditions() {
-	c.conditions = make([]HandlerFunc, 0)
+	c.conditions = make([]condition, 0)
+	c.invalidate()
+}
+
+// handlers returns the effects of the card followed by the handlers of its conditions
+func (c *Card) handlers() []HandlerFunc {
+	result := append(make([]HandlerFunc, 0), c.effects...)
+
+	for _, condition := range c.conditions {
+		result = append(result, condition.handler)
+	}
+
+	return result
+}
+
+// SetTapped taps or untaps the card
+func (c *Card) SetTapped(tapped bool) {
+	if c.Tapped == tapped {
+		return
+	}
+
+	c.Tapped = tapped
+	c.invalidate()
+}
+
+// invalidate drops the subscribers and characteristics kept by the match of the card, as its handlers
+// or attachments changed
+func (c *Card) invalidate() {
+	if c.player != nil {
+		c.player.match.invalidate()
+	}
 }
 
 // AttachedTo returns the card that this card is attached to
@@ -176,6 +214,7 @@ func (c *Card) AttachTo(card *Card) {
 	}
 
 	c.attachedTo = card
+	c.invalidate()
 
 	if err := c.MoveCard(SOUL); err != nil {
 		logrus.Debugf("AttachTo: %s", err)
@@ -186,6 +225,7 @@ func (c *Card) AttachTo(card *Card) {
 // Detach detaches a card
 func (c *Card) Detach() {
 	c.attachedTo = nil
+	c.invalidate()
 }
 
 // MoveCard tries to move a card to container b
@@ -210,6 +250,12 @@ func (c *Card) MoveCard(destination Container) error {
 
 	*from = temp
 
+	// Tokens only exist in play
+	if c.token && destination != BATTLEZONE && destination != SOUL {
+		c.vanish(destination)
+		return nil
+	}
+
 	*to = append(*to, c)
 
 	f := c.zone
@@ -228,7 +274,9 @@ func (c *Card) MoveCard(destination Container) error {
 
 	for _, card := range c.Attachments() {
 		card.attachedTo = c.attachedTo
-		c.MoveCard(destination)
+		if err := card.MoveCard(destination); err != nil {
+			logrus.Debugf("MoveCard: %s", err)
+		}
 	}
 
 	return nil
@@ -236,68 +284,43 @@ func (c *Card) MoveCard(destination Container) error {
 
 // GetRank returns the rank of a given card
 func (c *Card) GetRank(ctx *Context) uint8 {
-	e := &GetRankEvent{
-		ID:    c.id,
-		Event: ctx.event,
-		Rank:  c.rank,
-	}
-	ctx.match.HandleFx(NewContext(ctx.match, e))
-
-	return e.Rank
+	return ctx.match.stats(c).Rank
 }
 
 // GetCivilisation returns the Civ of a given card
 func (c *Card) GetCivilisation(ctx *Context) map[civ.Civilisation]bool {
-	e := &GetCivilisationEvent{
-		ID:    c.id,
-		Event: ctx.event,
-		Civ:   map[civ.Civilisation]bool{c.civ: true},
+	result := make(map[civ.Civilisation]bool)
+	for civ := range ctx.match.stats(c).Civ {
+		result[civ] = true
 	}
-	ctx.match.HandleFx(NewContext(ctx.match, e))
 
-	return e.Civ
+	return result
 }
 
 // GetFamily returns the family of a given card
 func (c *Card) GetFamily(ctx *Context) map[string]bool {
-	e := &GetFamilyEvent{
-		ID:     c.id,
-		Event:  ctx.event,
-		Family: map[string]bool{c.family: true},
+	result := make(map[string]bool)
+	for family := range ctx.match.stats(c).Family {
+		result[family] = true
 	}
-	ctx.match.HandleFx(NewContext(ctx.match, e))
 
-	return e.Family
+	return result
 }
 
 // GetAttack returns the attack of a given card
 func (c *Card) GetAttack(ctx *Context) uint8 {
-	e := &GetAttackEvent{
-		ID:     c.id,
-		Event:  ctx.event,
-		Attack: c.attack,
-	}
-	ctx.match.HandleFx(NewContext(ctx.match, e))
-
-	return e.Attack
+	return ctx.match.powerOf(c, ctx.event).Attack
 }
 
 // GetDefence returns the defence of a given card
 func (c *Card) GetDefence(ctx *Context) uint8 {
-	e := &GetDefenceEvent{
-		ID:      c.id,
-		Event:   ctx.event,
-		Defence: c.defence,
-	}
-	ctx.match.HandleFx(NewContext(ctx.match, e))
-
-	return e.Defence
+	return ctx.match.powerOf(c, ctx.event).Defence
 }
 
 // HasHandler returns true or false based on if c has the specified handler
 func (c *Card) HasHandler(handler HandlerFunc, ctx *Context) bool {
-	for _, condition := range append(c.effects, c.conditions...) {
-		if reflect.ValueOf(condition).Pointer() == reflect.ValueOf(handler).Pointer() {
+	for _, h := range c.handlers() {
+		if reflect.ValueOf(h).Pointer() == reflect.ValueOf(handler).Pointer() {
 			return true
 		}
 	}
@@ -306,22 +329,12 @@ func (c *Card) HasHandler(handler HandlerFunc, ctx *Context) bool {
 
 // HasFamily if card has given family
 func (c *Card) HasFamily(family string, ctx *Context) bool {
-	for f := range c.GetFamily(ctx) {
-		if family == f {
-			return true
-		}
-	}
-	return false
+	return ctx.match.stats(c).Family[family]
 }
 
 // HasCivilisation if card has given civilisation
 func (c *Card) HasCivilisation(civilisation civ.Civilisation, ctx *Context) bool {
-	for civ := range c.GetCivilisation(ctx) {
-		if civilisation == civ {
-			return true
-		}
-	}
-	return false
+	return ctx.match.stats(c).Civ[civilisation]
 }
 
 // RemoveEquipments ...
@@ -349,30 +362,49 @@ func (c *Card) EvolveTo(card *Card) {
 		return
 	}
 
-	card.Tapped = c.Tapped
+	card.SetTapped(c.Tapped)
 
 	c.AttachTo(card)
 
 	// This is done to maintain a single identity for a creature
 	c.id, card.id = card.id, c.id
 	c.conditions, card.conditions = card.conditions, c.conditions
+	c.counters, card.counters = card.counters, c.counters
+	c.invalidate()
 
 	c.player.match.Chat("Server", fmt.Sprintf("%s evolved %s to %s", c.player.Name(), c.name, card.name))
 }
 
-// SpellCast handles spell cast
+// SpellCast handles spell cast, the spell is put on the stack once its targets are chosen
 func (c *Card) SpellCast(ctx *Context, fx func() []*Card) {
 	for _, creature := range c.player.CollectCards(BATTLEZONE) {
 		if creature.HasCivilisation(c.civ, ctx) &&
 			c.GetRank(ctx) <= creature.GetRank(ctx) &&
 			!creature.Tapped {
 			ctx.ScheduleAfter(func() {
-				ctx.match.SpellCast(c.id, fx())
+				zone := c.zone
+				targets := fx()
 
-				if err := c.MoveCard(GRAVEYARD); err != nil {
-					logrus.Debug(err)
+				zones := make(map[*Card]Container)
+				for _, target := range targets {
+					zones[target] = target.zone
 				}
-				ctx.match.Chat("server", fmt.Sprintf("%s played %s", c.player.name, c.name))
+
+				ctx.match.Push(c, fmt.Sprintf("%s cast %s", c.player.name, c.name), func() {
+					// The spell is countered if it was removed in response
+					if c.zone != zone {
+						ctx.match.Chat("server", fmt.Sprintf("%s fizzled", c.name))
+						return
+					}
+
+					// Targets that were moved in response are no longer affected
+					ctx.match.SpellCast(c.id, Filter(targets, func(target *Card) bool { return target.zone == zones[target] }))
+
+					if err := c.MoveCard(GRAVEYARD); err != nil {
+						logrus.Debug(err)
+					}
+					ctx.match.Chat("server", fmt.Sprintf("%s played %s", c.player.name, c.name))
+				})
 			})
 			return
 		}
@@ -386,8 +418,12 @@ func (c *Card) denormalizeCard() CardState {
 		ID:            c.id,
 		UID:           c.cardID,
 		Name:          c.name,
+		Text:          c.text,
+		Keywords:      c.Keywords(),
 		Civ:           c.civ,
 		Tapped:        c.Tapped,
+		Token:         c.token,
+		Counters:      c.AllCounters(),
 		AttachedCards: denormalizeCards(c.Attachments()),
 	}
 }
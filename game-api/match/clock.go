@@ -0,0 +1,189 @@
+package match
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// TimeControl limits how long the players of a match may take. Zero values mean no limit
+type TimeControl struct {
+	// Turn is how long a turn may last before it is ended for the player
+	Turn time.Duration `json:"turn"`
+	// Clock is the total thinking time of each player, who loses the match once it runs out
+	Clock time.Duration `json:"clock"`
+	// Increment is added to a player's clock at the end of each of their turns
+	Increment time.Duration `json:"increment"`
+	// Prompt is how long a selection may stay open before it is answered for the player
+	Prompt time.Duration `json:"prompt"`
+}
+
+// SetTimeControl sets the time control of a match that has not started yet
+func (m *Match) SetTimeControl(tc TimeControl) error {
+	if m.started {
+		return errors.New("can't change the time control of a match that has started")
+	}
+
+	if err := tc.Validate(); err != nil {
+		return err
+	}
+
+	m.timeControl = tc
+
+	return nil
+}
+
+// Validate returns an error if the time control can't be used
+func (tc TimeControl) Validate() error {
+	if tc.Turn < 0 || tc.Clock < 0 || tc.Increment < 0 || tc.Prompt < 0 {
+		return errors.New("time limits can't be negative")
+	}
+
+	return nil
+}
+
+// TimeControl returns the time control of the match
+func (m *Match) TimeControl() TimeControl {
+	return m.timeControl
+}
+
+// runClock charges the time since the last switch to the player whose clock was running
+// and starts the clock of p instead
+func (m *Match) runClock(p *Player) {
+	m.clockMutex.Lock()
+	defer m.clockMutex.Unlock()
+
+	now := time.Now()
+
+	if m.clockRunning != nil {
+		m.clockRunning.clock -= now.Sub(m.clockSince)
+	}
+
+	m.clockRunning = p
+	m.clockSince = now
+}
+
+// startTurnClock starts the clock of the new turn player and gives the increment to the player
+// whose turn just ended
+func (m *Match) startTurnClock() {
+	p := m.CurrentPlayer()
+	opponent := m.Opponent(p)
+
+	m.runClock(p)
+
+	m.clockMutex.Lock()
+	defer m.clockMutex.Unlock()
+
+	if opponent.turnNo > 0 {
+		opponent.clock += m.timeControl.Increment
+	}
+
+	m.turnStarted = time.Now()
+}
+
+// remainingClock returns the thinking time the player has left
+func (m *Match) remainingClock(p *Player) time.Duration {
+	m.clockMutex.Lock()
+	defer m.clockMutex.Unlock()
+
+	if m.clockRunning == p {
+		return p.clock - time.Since(m.clockSince)
+	}
+
+	return p.clock
+}
+
+// remainingTurn returns the time left in the current turn
+func (m *Match) remainingTurn() time.Duration {
+	m.clockMutex.Lock()
+	defer m.clockMutex.Unlock()
+
+	return m.timeControl.Turn - time.Since(m.turnStarted)
+}
+
+// CheckTime enforces the time control, it should be called about every second. It only hands inputs
+// to the match on behalf of the players: a timeout for a player who ran out of clock, DefaultDecision
+// for selections that were open too long and end_turn for turns that lasted too long
+func (m *Match) CheckTime() {
+	if !m.started || m.ended || m.Paused() {
+		return
+	}
+
+	tc := m.timeControl
+
+	if tc.Clock > 0 {
+		for _, p := range m.Players() {
+			if m.remainingClock(p) <= 0 {
+				m.submit(p, Message{Header: "timeout"})
+				return
+			}
+		}
+	}
+
+	if tc.Prompt > 0 {
+		for _, p := range m.Players() {
+			if prompt, opened := p.openedPrompt(); prompt != nil && time.Since(opened) >= tc.Prompt {
+				m.submit(p, decisionMessage(DefaultDecision(*prompt)))
+			}
+		}
+	}
+
+	// The first turn has not begun during the mulligan
+	if tc.Turn > 0 && m.phase != MulliganPhase && m.remainingTurn() <= 0 {
+		busy := false
+
+		// Selections hold up the turn, so they are answered before it can be ended
+		for _, p := range m.Players() {
+			if prompt, _ := p.openedPrompt(); prompt != nil {
+				m.submit(p, decisionMessage(DefaultDecision(*prompt)))
+			}
+
+			busy = busy || p.busy()
+		}
+
+		// The input that is being resolved finishes first, the turn is ended on a later check
+		if !busy {
+			m.submit(m.CurrentPlayer(), Message{Header: "end_turn"})
+		}
+	}
+}
+
+// submit hands an input to the match on behalf of the player, the same way the inputs of their client
+// arrive. It is resolved on its own goroutine, as it may wait for prompts to be answered
+func (m *Match) submit(p *Player, msg interface{}) {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		logrus.Debug(err)
+		return
+	}
+
+	go m.Input(p, data)
+}
+
+// decisionMessage returns the input that answers a prompt with the decision
+func decisionMessage(decision Decision) interface{} {
+	if decision.Cancel {
+		return Message{Header: "cancel"}
+	}
+
+	return struct {
+		Header string   `json:"header"`
+		Cards  []string `json:"cards"`
+	}{"action", decision.Cards}
+}
+
+// timeout ends the match in favour of the opponent of the player who ran out of time. A match without
+// a clock takes the timeout as it is, as it can then only come from a journal
+func (m *Match) timeout(p *Player, data []byte) {
+	if m.timeControl.Clock > 0 && m.remainingClock(p) > 0 {
+		Warn(p, "You have time left")
+		return
+	}
+
+	m.record(p, "timeout", data)
+
+	m.End(m.Opponent(p), fmt.Sprintf("%s ran out of time", p.Name()))
+}
@@ -0,0 +1,222 @@
+package match
+
+import (
+	"sort"
+
+	"github.com/sirupsen/logrus"
+)
+
+// discardWriter is the writer of a cloned player, clones are silent
+type discardWriter struct {
+	// player keeps the writers of both players apart, PlayerForWriter tells players apart by writer
+	player int
+}
+
+// Write drops the message
+func (w *discardWriter) Write(msg interface{}) {}
+
+// Clone returns a deep copy of the match, including the state of its random source, that can be
+// played forward without affecting the original. The copy sends no output to anyone and its
+// players answer prompts with DefaultDecision until they are given another Decider.
+//
+// A match is only cloned between inputs, an input that is being resolved is waited for
+func (m *Match) Clone() *Match {
+	m.holdInputs()
+	defer m.endInput()
+
+	clone := NewWithSeed(m.seed)
+	clone.src.state = m.src.state
+	clone.journal.Players = m.Journal().Players
+	clone.started = m.started
+	clone.phase = m.phase
+	clone.mulligan = m.mulligan
+	clone.ended = m.ended
+	clone.headless = true
+
+	clone.timeControl = m.timeControl
+	clone.clockSince = m.clockSince
+	clone.turnStarted = m.turnStarted
+	clone.pausedAt = m.pausedAt
+
+	cards := make(map[*Card]*Card)
+
+	if m.player1 != nil {
+		clone.player1 = m.player1.clone(clone, &discardWriter{player: 1}, cards)
+	}
+
+	if m.player2 != nil {
+		clone.player2 = m.player2.clone(clone, &discardWriter{player: 2}, cards)
+	}
+
+	for original, c := range cards {
+		if original.attachedTo != nil {
+			c.attachedTo = cards[original.attachedTo]
+		}
+	}
+
+	switch m.winner {
+	case m.player1:
+		clone.winner = clone.player1
+	case m.player2:
+		clone.winner = clone.player2
+	}
+
+	switch m.drawOffer {
+	case m.player1:
+		clone.drawOffer = clone.player1
+	case m.player2:
+		clone.drawOffer = clone.player2
+	}
+
+	m.clockMutex.Lock()
+	switch m.clockRunning {
+	case m.player1:
+		clone.clockRunning = clone.player1
+	case m.player2:
+		clone.clockRunning = clone.player2
+	}
+	m.clockMutex.Unlock()
+
+	return clone
+}
+
+// clone copies the player and their cards into the given match
+func (p *Player) clone(m *Match, w Writer, cards map[*Card]*Card) *Player {
+	clone := newPlayer(p.name, w, m, p.turn)
+	clone.life = p.life
+	clone.ready = p.ready
+	clone.turnNo = p.turnNo
+	clone.clock = p.clock
+	clone.lost = p.lost
+	clone.decider = DeciderFunc(func(p *Player, prompt Prompt) Decision {
+		return DefaultDecision(prompt)
+	})
+
+	for _, container := range AllContainers() {
+		// Reading through Container leaves the subscribers of the original match alone
+		from, _ := p.Container(container)
+		to, _ := clone.containerRef(container)
+
+		for _, c := range from {
+			card := c.clone(clone)
+			cards[c] = card
+			*to = append(*to, card)
+		}
+	}
+
+	return clone
+}
+
+// clone copies the card for the given player, attachments are restored by Match.Clone
+func (c *Card) clone(p *Player) *Card {
+	clone := *c
+	clone.player = p
+	clone.attachedTo = nil
+	clone.conditions = append(make([]condition, 0), c.conditions...)
+	clone.counters = c.AllCounters()
+
+	return &clone
+}
+
+// Players returns both players of the match, the first one being the one who created it
+func (m *Match) Players() []*Player {
+	players := make([]*Player, 0)
+
+	for _, p := range []*Player{m.player1, m.player2} {
+		if p != nil {
+			players = append(players, p)
+		}
+	}
+
+	return players
+}
+
+// Determinize replaces everything the observer can't see with a random sample. The match's random
+// source is reseeded with seed. The observer only knows how many cards are in the opponent's hand,
+// trap zone and deck, and the cards the opponent has shown in their other zones. The hidden cards are
+// drawn first from the copies of the shown cards the opponent's deck may still hold, then from every
+// card there is, each keeping its id. The observer's own deck is shuffled.
+// It is meant to be used on clones, to guess at the hidden state of a match
+func (m *Match) Determinize(observer *Player, seed int64) {
+	m.src.Seed(seed)
+
+	opponent := m.Opponent(observer)
+
+	shown := make(map[int]int)
+	for _, c := range opponent.CollectCards(BATTLEZONE, GRAVEYARD, SOUL) {
+		if !c.token {
+			shown[c.cardID]++
+		}
+	}
+
+	likely := make([]int, 0)
+	for id, n := range shown {
+		for i := n; i < maxCopies; i++ {
+			likely = append(likely, id)
+		}
+	}
+	sort.Ints(likely)
+
+	pool := make([]int, 0)
+	for id := range ctors {
+		pool = append(pool, id)
+	}
+	sort.Ints(pool)
+
+	// The hidden cards are visited in random order, so that the likely ones can end up anywhere
+	type slot struct {
+		container Container
+		cards     *[]*Card
+		i         int
+	}
+
+	slots := make([]slot, 0)
+	for _, container := range []Container{HAND, TRAPZONE, DECK} {
+		ref, _ := opponent.containerRef(container)
+
+		for i := range *ref {
+			slots = append(slots, slot{container: container, cards: ref, i: i})
+		}
+	}
+
+	m.rand.Shuffle(len(slots), func(i, j int) {
+		slots[i], slots[j] = slots[j], slots[i]
+	})
+
+	for _, s := range slots {
+		var cardID int
+
+		switch {
+		case len(likely) > 0:
+			n := m.rand.Intn(len(likely))
+			cardID = likely[n]
+			likely = append(likely[:n], likely[n+1:]...)
+		case len(pool) > 0:
+			cardID = pool[m.rand.Intn(len(pool))]
+		default:
+			continue
+		}
+
+		card, err := CardCtor(cardID)
+		if err != nil {
+			logrus.Debug(err)
+			continue
+		}
+
+		c := (*s.cards)[s.i]
+
+		card.id = c.id
+		card.cardID = cardID
+		card.player = opponent
+		card.zone = s.container
+		card.Tapped = c.Tapped
+
+		(*s.cards)[s.i] = card
+	}
+
+	m.rand.Shuffle(len(observer.deck), func(i, j int) {
+		observer.deck[i], observer.deck[j] = observer.deck[j], observer.deck[i]
+	})
+
+	m.invalidate()
+}
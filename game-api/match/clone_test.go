@@ -0,0 +1,136 @@
+package match_test
+
+import (
+	"encoding/json"
+	"math/rand"
+	"testing"
+
+	"github.com/jyotiskaghosh/ganjifa/game-api/match"
+)
+
+// stored returns the snapshot of the match as JSON, without what changes with the wall clock.
+// Clones don't keep a journal, so it is left out
+func stored(t *testing.T, m *match.Match) string {
+	s, err := m.Snapshot()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s.Journal = match.Journal{}
+
+	data, err := json.Marshal(timeless(s))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return string(data)
+}
+
+func TestCloneIsolation(t *testing.T) {
+	m, _ := newMatch(t, 11)
+	play(m, 11, 20)
+
+	if m.Ended() {
+		t.Fatal("the match ended before it could be cloned")
+	}
+
+	before := stored(t, m)
+	clone := m.Clone()
+
+	if stored(t, clone) != before {
+		t.Fatal("the clone differs from the match")
+	}
+
+	rng := rand.New(rand.NewSource(11))
+	for i := 0; i < 30 && !clone.Ended(); i++ {
+		move(clone, rng)
+	}
+
+	if stored(t, clone) == before {
+		t.Fatal("playing on the clone didn't change it")
+	}
+
+	if stored(t, m) != before {
+		t.Fatal("playing on the clone changed the match")
+	}
+
+	cloned := stored(t, clone)
+	play(m, 12, 30)
+
+	if stored(t, clone) != cloned {
+		t.Fatal("playing on the match changed the clone")
+	}
+}
+
+func TestDeterminize(t *testing.T) {
+	m, _ := newMatch(t, 11)
+	play(m, 11, 40)
+
+	if m.Ended() {
+		t.Fatal("the match ended before it could be determinized")
+	}
+
+	observer, opponent := m.Players()[0], m.Players()[1]
+	hidden := []match.Container{match.HAND, match.TRAPZONE, match.DECK}
+
+	shown := make(map[int]bool)
+	for _, c := range opponent.CollectCards(match.BATTLEZONE, match.GRAVEYARD, match.SOUL) {
+		shown[c.CardID()] = true
+	}
+
+	if len(shown) < 1 {
+		t.Fatal("the opponent hasn't shown any card")
+	}
+
+	guess := func(seed int64) *match.Match {
+		clone := m.Clone()
+		clone.Determinize(clone.Players()[0], seed)
+
+		return clone
+	}
+
+	clone := guess(1)
+	guessed := clone.Players()[1].CollectCards(hidden...)
+
+	if len(guessed) != len(opponent.CollectCards(hidden...)) {
+		t.Fatal("the opponent's hidden cards changed in number")
+	}
+
+	likely := 0
+	for _, c := range guessed {
+		if shown[c.CardID()] {
+			likely++
+		}
+	}
+
+	// Every shown card may have up to 3 more copies hidden, which are used before any other card
+	if max := 3 * len(shown); likely < max && likely < len(guessed) {
+		t.Fatalf("only %d hidden cards were guessed from the shown ones", likely)
+	}
+
+	if stored(t, guess(1)) != stored(t, clone) {
+		t.Fatal("determinizing with the same seed gave different matches")
+	}
+
+	for _, c := range clone.Players()[0].CollectCards(match.HAND, match.TRAPZONE) {
+		if !observer.HasCard(c.ID(), match.HAND, match.TRAPZONE) {
+			t.Fatalf("the observer's own card %s was replaced", c.ID())
+		}
+	}
+}
+
+func TestCloneBetweenInputs(t *testing.T) {
+	m, _ := newMatch(t, 13)
+
+	done := make(chan bool)
+	go func() {
+		defer close(done)
+
+		for i := 0; i < 20; i++ {
+			m.Clone()
+		}
+	}()
+
+	play(m, 13, 100)
+	<-done
+}
@@ -0,0 +1,114 @@
+package match
+
+import (
+	"fmt"
+	"reflect"
+	"runtime"
+)
+
+var conditions = make(map[string]HandlerFunc)
+var conditionNames = make(map[uintptr]string)
+
+// RegisterCondition makes a handler that is used as a condition known by name, so that
+// cards carrying it can be stored in a snapshot. It should be called from init functions,
+// and the handler must be a plain function, closures can't be told apart
+func RegisterCondition(name string, condition HandlerFunc) {
+	ptr := reflect.ValueOf(condition).Pointer()
+
+	if registered, ok := conditionNames[ptr]; ok && registered != name {
+		panic(fmt.Sprintf("condition %s has the code of condition %s, they can't be told apart", name, registered))
+	}
+
+	conditions[name] = condition
+	conditionNames[ptr] = name
+}
+
+// Condition returns the condition registered with the given name
+func Condition(name string) (HandlerFunc, error) {
+	condition, ok := conditions[name]
+	if !ok {
+		return nil, fmt.Errorf("condition %s is not registered", name)
+	}
+
+	return condition, nil
+}
+
+// conditionName returns the name a condition was registered with
+func conditionName(condition HandlerFunc) (string, error) {
+	ptr := reflect.ValueOf(condition).Pointer()
+
+	name, ok := conditionNames[ptr]
+	if !ok {
+		return "", fmt.Errorf("condition %s is not registered", runtime.FuncForPC(ptr).Name())
+	}
+
+	return name, nil
+}
+
+// Expiry is the kind of step at which a condition expires
+type Expiry uint8
+
+// Expiries
+const (
+	// Never expires
+	Never Expiry = iota
+	// EndOfTurn expires once the end step of a number of turns is over
+	EndOfTurn
+	// NextTurn expires at the untap step of the next turn of the card's owner
+	NextTurn
+)
+
+// Duration tells how long a condition lasts
+type Duration struct {
+	Until Expiry `json:"until"`
+	// Turns is the number of end steps left before an EndOfTurn condition expires
+	Turns int `json:"turns"`
+}
+
+// Durations
+var (
+	// UntilEndOfTurn lasts until the end of the current turn
+	UntilEndOfTurn = Duration{Until: EndOfTurn, Turns: 1}
+	// UntilYourNextTurn lasts until the next turn of the card's owner begins
+	UntilYourNextTurn = Duration{Until: NextTurn}
+	// Permanent lasts as long as the card
+	Permanent = Duration{Until: Never}
+)
+
+// ForTurns lasts until the end of the nth turn, counting the current one as the first
+func ForTurns(n int) Duration {
+	return Duration{Until: EndOfTurn, Turns: n}
+}
+
+// condition is a temporary handler of a card and how long it lasts
+type condition struct {
+	handler  HandlerFunc
+	duration Duration
+}
+
+// expireConditions removes the conditions of the player's cards that expire at the given kind of step,
+// EndOfTurn conditions only expire once their last turn is over
+func (p *Player) expireConditions(until Expiry) {
+	for _, c := range p.CollectCards(AllContainers()...) {
+		tmp := make([]condition, 0)
+
+		for _, cond := range c.conditions {
+			switch {
+			case cond.duration.Until != until:
+			case until == EndOfTurn && cond.duration.Turns > 1:
+				cond.duration.Turns--
+			default:
+				continue
+			}
+
+			tmp = append(tmp, cond)
+		}
+
+		expired := len(tmp) != len(c.conditions)
+		c.conditions = tmp
+
+		if expired {
+			c.invalidate()
+		}
+	}
+}
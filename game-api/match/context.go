@@ -7,6 +7,9 @@ type Context struct {
 	event   interface{}
 	cancel  bool
 	postFxs []func()
+
+	// dryRun contexts only find out whether the event would be interrupted
+	dryRun bool
 }
 
 // HandlerFunc is a function with a match context as argument
@@ -20,6 +23,12 @@ func NewContext(m *Match, e interface{}) *Context {
 	}
 }
 
+// DryRun returns true if the context only checks whether the event would be interrupted,
+// handlers should not warn players or change anything then
+func (ctx *Context) DryRun() bool {
+	return ctx.dryRun
+}
+
 // Match ...
 func (ctx *Context) Match() *Match {
 	return ctx.match
@@ -0,0 +1,72 @@
+package match
+
+// Counters the engine applies to the stats of a card, other counters only mean what cards make of them
+const (
+	// AttackCounter adds 1 to the attack of the card for each counter
+	AttackCounter = "attack"
+	// DefenceCounter adds 1 to the defence of the card for each counter
+	DefenceCounter = "defence"
+)
+
+// Counters returns the number of counters with the given name on the card
+func (c *Card) Counters(name string) int {
+	return c.counters[name]
+}
+
+// AllCounters returns a copy of every counter on the card
+func (c *Card) AllCounters() map[string]int {
+	result := make(map[string]int)
+
+	for name, n := range c.counters {
+		result[name] = n
+	}
+
+	return result
+}
+
+// AddCounters puts n counters with the given name on the card
+func (c *Card) AddCounters(name string, n int) {
+	if n <= 0 {
+		return
+	}
+
+	c.setCounters(name, c.counters[name]+n)
+}
+
+// RemoveCounters takes up to n counters with the given name off the card
+func (c *Card) RemoveCounters(name string, n int) {
+	if n <= 0 || c.counters[name] == 0 {
+		return
+	}
+
+	count := c.counters[name] - n
+	if count < 0 {
+		count = 0
+	}
+
+	c.setCounters(name, count)
+}
+
+// setCounters changes the number of counters and fires a CounterChanged event
+func (c *Card) setCounters(name string, count int) {
+	previous := c.counters[name]
+
+	if c.counters == nil {
+		c.counters = make(map[string]int)
+	}
+
+	if count > 0 {
+		c.counters[name] = count
+	} else {
+		delete(c.counters, name)
+	}
+
+	c.invalidate()
+
+	c.player.match.HandleFx(NewContext(c.player.match, &CounterChanged{
+		ID:       c.id,
+		Counter:  name,
+		Previous: previous,
+		Count:    count,
+	}))
+}
@@ -0,0 +1,103 @@
+package match
+
+// Prompt is a selection of cards a player is asked to make
+type Prompt struct {
+	Cards       []*Card
+	Text        string
+	Min         int
+	Max         int
+	Cancellable bool
+
+	// Source is the card the prompt is about, such as the attacker when asked to respond to an attack
+	Source *Card
+
+	// Response is set when the player is given priority to respond to the top of the stack
+	Response bool
+}
+
+// accepts returns true if the decision is a valid answer to the prompt
+func (prompt Prompt) accepts(decision Decision) bool {
+	if decision.Cancel {
+		return prompt.Cancellable
+	}
+
+	return len(decision.Cards) >= prompt.Min &&
+		len(decision.Cards) <= prompt.Max &&
+		AssertCardsIn(prompt.Cards, decision.Cards...)
+}
+
+// Decision is the answer to a Prompt
+type Decision struct {
+	Cards  []string
+	Cancel bool
+}
+
+// Decider answers the prompts of a player
+type Decider interface {
+	Decide(p *Player, prompt Prompt) Decision
+}
+
+// DeciderFunc allows a plain function to be used as a Decider
+type DeciderFunc func(p *Player, prompt Prompt) Decision
+
+// Decide calls f
+func (f DeciderFunc) Decide(p *Player, prompt Prompt) Decision {
+	return f(p, prompt)
+}
+
+// DefaultDecision returns the answer used when a prompt can't be answered otherwise,
+// which is to cancel if possible or else select the first cards required
+func DefaultDecision(prompt Prompt) Decision {
+	if prompt.Cancellable {
+		return Decision{Cancel: true}
+	}
+
+	cards := make([]string, 0)
+
+	for i := 0; i < prompt.Min && i < len(prompt.Cards); i++ {
+		cards = append(cards, prompt.Cards[i].id)
+	}
+
+	return Decision{Cards: cards}
+}
+
+// SocketDecider answers prompts with the selections the player sends from their client,
+// which arrive through Match.Parse as action and cancel messages
+type SocketDecider struct {
+	answers chan Decision
+}
+
+// NewSocketDecider returns a new SocketDecider
+func NewSocketDecider() *SocketDecider {
+	return &SocketDecider{
+		answers: make(chan Decision, 1),
+	}
+}
+
+// Decide waits for the player to answer the prompt. Answers are handed over while the prompt is open,
+// so one may already be waiting
+func (d *SocketDecider) Decide(p *Player, prompt Prompt) Decision {
+	return <-d.answers
+}
+
+// Answer hands a selection sent by the player to the prompt that is waiting for it
+func (d *SocketDecider) Answer(decision Decision) {
+	select {
+	case d.answers <- decision:
+	default:
+	}
+}
+
+// discard drops an answer that was handed over but never used
+func (d *SocketDecider) discard() {
+	select {
+	case <-d.answers:
+	default:
+	}
+}
+
+// answerer is implemented by deciders that are fed answers from Match.Parse
+type answerer interface {
+	Answer(decision Decision)
+	discard()
+}
@@ -0,0 +1,91 @@
+package match
+
+import (
+	"reflect"
+	"sync"
+)
+
+var subscriptions = make(map[uintptr]map[reflect.Type]bool)
+var subscriptionsMutex = &sync.RWMutex{}
+
+// Subscribe declares the events a handler handles and returns the handler, so that handlers written in
+// place can be wrapped. Events are given as values of their type, such as &UntapStep{}, and the handler
+// is then only called for events of these types. Handlers that never subscribe are called for every event.
+// Subscriptions are told apart by the code of the handler, every card built from the same function
+// literal shares them
+func Subscribe(handler HandlerFunc, events ...interface{}) HandlerFunc {
+	ptr := reflect.ValueOf(handler).Pointer()
+
+	subscriptionsMutex.Lock()
+	defer subscriptionsMutex.Unlock()
+
+	types, ok := subscriptions[ptr]
+	if !ok {
+		types = make(map[reflect.Type]bool)
+		subscriptions[ptr] = types
+	}
+
+	for _, event := range events {
+		types[reflect.TypeOf(event)] = true
+	}
+
+	return handler
+}
+
+// subscriber is a handler of a card
+type subscriber struct {
+	card    *Card
+	handler HandlerFunc
+}
+
+// subscribers returns the handlers that are called for events of the given type, in the order they are
+// called: the cards of the player in which turn it is first, and the effects of each card before its
+// conditions. The list is kept until the cards change
+func (m *Match) subscribers(event reflect.Type) []subscriber {
+	m.indexMutex.Lock()
+	defer m.indexMutex.Unlock()
+
+	if result, ok := m.index[event]; ok {
+		return result
+	}
+
+	first, second := m.player1, m.player2
+	if !m.player1.turn {
+		first, second = m.player2, m.player1
+	}
+
+	result := make([]subscriber, 0)
+
+	subscriptionsMutex.RLock()
+
+	for _, c := range append(first.CollectCards(AllContainers()...), second.CollectCards(AllContainers()...)...) {
+		for _, h := range c.handlers() {
+			types, ok := subscriptions[reflect.ValueOf(h).Pointer()]
+
+			if !ok || types[event] {
+				result = append(result, subscriber{card: c, handler: h})
+			}
+		}
+	}
+
+	subscriptionsMutex.RUnlock()
+
+	if m.index == nil {
+		m.index = make(map[reflect.Type][]subscriber)
+	}
+
+	m.index[event] = result
+
+	return result
+}
+
+// invalidate drops the kept subscribers and characteristics. It must be called whenever cards move,
+// change order, gain or lose handlers or get attached, and when the turn passes
+func (m *Match) invalidate() {
+	m.indexMutex.Lock()
+	defer m.indexMutex.Unlock()
+
+	m.index = nil
+	m.cache = nil
+	m.uses = nil
+}
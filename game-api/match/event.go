@@ -1,12 +1,14 @@
 package match
 
-import (
-	"github.com/jyotiskaghosh/ganjifa/game-api/civ"
-)
-
 // EndTurnEvent is fired when a player attempts to end their turn
 type EndTurnEvent struct{}
 
+// PhaseChanged is fired when the turn moves from one phase to another
+type PhaseChanged struct {
+	Phase    Phase
+	Previous Phase
+}
+
 // PlayCardEvent is fired when the player attempts to play a card
 type PlayCardEvent struct {
 	ID string
@@ -30,6 +32,19 @@ type SpellCast struct {
 	Targets []*Card
 }
 
+// TokenCreated is fired after a token was created in a battlezone
+type TokenCreated struct {
+	ID string
+}
+
+// TokenVanished is fired after a token was taken out of the match instead of leaving play,
+// To is where it would have gone
+type TokenVanished struct {
+	ID   string
+	From Container
+	To   Container
+}
+
 // CardMoved is fired from the *Player.MoveCard method after moving a card between containers
 type CardMoved struct {
 	ID   string
@@ -48,10 +63,14 @@ type AttackCreature struct {
 	TargetID string
 }
 
-// TrapEvent is fired when you play cards from hiddenzone during opponent's attack
+// TrapEvent is fired when you play cards from hiddenzone during opponent's attack or in response to the stack
 type TrapEvent struct {
-	ID       string
+	ID string
+	// Attacker is nil when the card is played in response to the stack
 	Attacker *Card
+	// Activated is set by a card that has an effect when activated this way, a dry run of the event
+	// tells whether the card may be activated
+	Activated bool
 }
 
 // BlockEvent is fired when a creature attempts to block an incoming attack
@@ -89,39 +108,40 @@ type HealEvent struct {
 	Health uint8
 }
 
-// GetAttackEvent is fired whenever a card's attack is to be used
-type GetAttackEvent struct {
-	ID     string
-	Event  interface{}
-	Attack uint8
-}
-
-// GetDefenceEvent is fired whenever a card's defence is to be used
-type GetDefenceEvent struct {
-	ID      string
-	Event   interface{}
-	Defence uint8
+// CounterChanged is fired after counters were put on or taken off a card
+type CounterChanged struct {
+	ID       string
+	Counter  string
+	Previous int
+	Count    int
 }
 
-// GetCivilisationEvent is fired whenever a card's civ is to be used
-type GetCivilisationEvent struct {
+// ContinuousEvent is fired once for every layer when the characteristics of a card are computed,
+// handlers apply their continuous effects to Stats. It is only fired again after the cards change, so
+// effects may not depend on what the card is used for, such as the creature it attacks or blocks. Those
+// bonuses go through GetAttackEvent and GetDefenceEvent
+type ContinuousEvent struct {
 	ID    string
-	Event interface{}
-	Civ   map[civ.Civilisation]bool
+	Layer Layer
+	Stats *Stats
 }
 
-// GetFamilyEvent is fired whenever a card's family is to be used
-type GetFamilyEvent struct {
+// GetAttackEvent is fired when a card's attack is to be used, for bonuses that depend on what it
+// is used for. Attack already holds the continuous effects, it is kept from 0 to 255 once every bonus is
+// added. The result is kept for the type of Event until the cards change, so bonuses may only look at its type
+type GetAttackEvent struct {
 	ID     string
 	Event  interface{}
-	Family map[string]bool
+	Attack int
 }
 
-// GetRankEvent is fired whenever a card's rank is to be used
-type GetRankEvent struct {
-	ID    string
-	Event interface{}
-	Rank  uint8
+// GetDefenceEvent is fired when a card's defence is to be used, for bonuses that depend on what it
+// is used for. Defence already holds the continuous effects, it is kept from 0 to 255 once every bonus is
+// added. The result is kept for the type of Event until the cards change, so bonuses may only look at its type
+type GetDefenceEvent struct {
+	ID      string
+	Event   interface{}
+	Defence int
 }
 
 // GetHandlerEvent is fired whenever a card's handlers are to be used
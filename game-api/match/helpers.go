@@ -56,7 +56,7 @@ func Devolve(card *Card, src *Card) {
 		return
 	}
 
-	cards[0].Tapped = card.Tapped
+	cards[0].SetTapped(card.Tapped)
 
 	for _, card := range card.Attachments() {
 		card.AttachTo(cards[0])
@@ -65,6 +65,8 @@ func Devolve(card *Card, src *Card) {
 	// This is done to maintain a single identity for a creature
 	card.id, cards[0].id = cards[0].id, card.id
 	card.conditions, cards[0].conditions = cards[0].conditions, card.conditions
+	card.counters, cards[0].counters = cards[0].counters, card.counters
+	card.invalidate()
 
 	card.player.match.Destroy(card, src)
 }
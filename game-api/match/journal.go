@@ -0,0 +1,56 @@
+package match
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// JournalEntry is a single accepted player input or prompt answer
+type JournalEntry struct {
+	Seq    int             `json:"seq"`
+	Time   time.Time       `json:"time"`
+	Player int             `json:"player"`
+	Header string          `json:"header"`
+	Data   json.RawMessage `json:"data"`
+}
+
+// Journal is the ordered record of everything the players did in a match.
+// Together with the seed it is enough to reproduce the match exactly
+type Journal struct {
+	Seed     int64          `json:"seed"`
+	Mulligan Mulligan       `json:"mulligan"`
+	Players  []string       `json:"players"`
+	Entries  []JournalEntry `json:"entries"`
+}
+
+// Journal returns a copy of the match's journal
+func (m *Match) Journal() Journal {
+	m.journalMutex.Lock()
+	defer m.journalMutex.Unlock()
+
+	return Journal{
+		Seed:     m.journal.Seed,
+		Mulligan: m.journal.Mulligan,
+		Players:  append(make([]string, 0), m.journal.Players...),
+		Entries:  append(make([]JournalEntry, 0), m.journal.Entries...),
+	}
+}
+
+// record appends an input of the given player to the journal
+func (m *Match) record(p *Player, header string, data []byte) {
+	m.journalMutex.Lock()
+	defer m.journalMutex.Unlock()
+
+	player := 1
+	if p == m.player2 {
+		player = 2
+	}
+
+	m.journal.Entries = append(m.journal.Entries, JournalEntry{
+		Seq:    len(m.journal.Entries),
+		Time:   time.Now(),
+		Player: player,
+		Header: header,
+		Data:   append(json.RawMessage{}, data...),
+	})
+}
@@ -0,0 +1,29 @@
+package match
+
+import (
+	"reflect"
+)
+
+var keywords = make(map[uintptr]string)
+
+// RegisterKeyword makes a handler known as a keyword, so that cards carrying it can show it.
+// It should be called from init functions, and the handler must be a plain function
+func RegisterKeyword(name string, handler HandlerFunc) {
+	keywords[reflect.ValueOf(handler).Pointer()] = name
+}
+
+// Keywords returns the keywords of the card's effects and conditions, each once
+func (c *Card) Keywords() []string {
+	result := make([]string, 0)
+	seen := make(map[string]bool)
+
+	for _, h := range c.handlers() {
+		name, ok := keywords[reflect.ValueOf(h).Pointer()]
+		if ok && !seen[name] {
+			seen[name] = true
+			result = append(result, name)
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,137 @@
+package match
+
+import (
+	"reflect"
+
+	"github.com/jyotiskaghosh/ganjifa/game-api/civ"
+)
+
+// Layer orders continuous effects, every effect of a layer is applied before those of the next
+type Layer uint8
+
+// Layers
+const (
+	// CharacteristicLayer changes the rank, civilisations and families of a card
+	CharacteristicLayer Layer = iota
+	// ModifierLayer adds to or subtracts from the attack and defence of a card
+	ModifierLayer
+	// MultiplierLayer scales the attack and defence of a card, once every bonus is added
+	MultiplierLayer
+)
+
+// layers lists the layers in the order they are applied
+var layers = []Layer{CharacteristicLayer, ModifierLayer, MultiplierLayer}
+
+// Stats are the characteristics of a card once continuous effects are applied. Effects may take the
+// attack and defence out of 0 to 255 while the layers are applied, they are kept in range after the last one
+type Stats struct {
+	Rank    uint8
+	Civ     map[civ.Civilisation]bool
+	Family  map[string]bool
+	Attack  int
+	Defence int
+}
+
+// stats returns the characteristics of the card, they are computed once and kept until the cards change.
+// While they are computed effects see the layers that were already applied
+func (m *Match) stats(c *Card) *Stats {
+	m.indexMutex.Lock()
+	s, ok := m.cache[c]
+	m.indexMutex.Unlock()
+
+	if ok {
+		return s
+	}
+
+	s = &Stats{
+		Rank:    c.rank,
+		Civ:     map[civ.Civilisation]bool{c.civ: true},
+		Family:  map[string]bool{c.family: true},
+		Attack:  int(addCounters(c.attack, c.counters[AttackCounter])),
+		Defence: int(addCounters(c.defence, c.counters[DefenceCounter])),
+	}
+
+	m.indexMutex.Lock()
+	if m.cache == nil {
+		m.cache = make(map[*Card]*Stats)
+	}
+	m.cache[c] = s
+	m.indexMutex.Unlock()
+
+	for _, layer := range layers {
+		m.handle(NewContext(m, &ContinuousEvent{
+			ID:    c.id,
+			Layer: layer,
+			Stats: s,
+		}))
+	}
+
+	s.Attack = int(clamp(s.Attack))
+	s.Defence = int(clamp(s.Defence))
+
+	return s
+}
+
+// power is the attack and defence of a card
+type power struct {
+	Attack  uint8
+	Defence uint8
+}
+
+// usage is a card and the type of the event its power is used for
+type usage struct {
+	card  *Card
+	event reflect.Type
+}
+
+// powerOf returns the power of the card when it is used for the event. Bonuses that depend on what it is
+// used for are asked through GetAttackEvent and GetDefenceEvent, they may only look at the type of the
+// event so that the power is kept for each type until the cards change
+func (m *Match) powerOf(c *Card, event interface{}) power {
+	key := usage{card: c, event: reflect.TypeOf(event)}
+
+	m.indexMutex.Lock()
+	p, ok := m.uses[key]
+	m.indexMutex.Unlock()
+
+	if ok {
+		return p
+	}
+
+	s := m.stats(c)
+
+	attack := &GetAttackEvent{ID: c.id, Event: event, Attack: s.Attack}
+	m.handle(NewContext(m, attack))
+
+	defence := &GetDefenceEvent{ID: c.id, Event: event, Defence: s.Defence}
+	m.handle(NewContext(m, defence))
+
+	p = power{Attack: clamp(attack.Attack), Defence: clamp(defence.Defence)}
+
+	m.indexMutex.Lock()
+	if m.uses == nil {
+		m.uses = make(map[usage]power)
+	}
+	m.uses[key] = p
+	m.indexMutex.Unlock()
+
+	return p
+}
+
+// addCounters returns the stat with the counters added, kept from 0 to 255
+func addCounters(stat uint8, counters int) uint8 {
+	return clamp(int(stat) + counters)
+}
+
+// clamp returns the stat kept from 0 to 255
+func clamp(stat int) uint8 {
+	if stat < 0 {
+		return 0
+	}
+
+	if stat > 255 {
+		return 255
+	}
+
+	return uint8(stat)
+}
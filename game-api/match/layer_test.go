@@ -0,0 +1,93 @@
+package match_test
+
+import (
+	"testing"
+
+	"github.com/jyotiskaghosh/ganjifa/game-api/fx"
+	"github.com/jyotiskaghosh/ganjifa/game-api/match"
+)
+
+// bonus adds n to the attack of the card
+func bonus(n uint8) match.HandlerFunc {
+	return match.Subscribe(func(card *match.Card, ctx *match.Context) {
+		fx.AttackModifier(card, ctx, n)
+	}, &match.ContinuousEvent{})
+}
+
+// double doubles the attack of the card
+var double = match.Subscribe(func(card *match.Card, ctx *match.Context) {
+	if event, ok := ctx.Event().(*match.ContinuousEvent); ok && event.Layer == match.MultiplierLayer && event.ID == card.ID() {
+		event.Stats.Attack *= 2
+	}
+}, &match.ContinuousEvent{})
+
+// guard adds 1 to the defence of the card while it is untapped
+var guard = match.Subscribe(func(card *match.Card, ctx *match.Context) {
+	if event, ok := ctx.Event().(*match.ContinuousEvent); ok && event.Layer == match.ModifierLayer && event.ID == card.ID() && !card.Tapped {
+		event.Stats.Defence++
+	}
+}, &match.ContinuousEvent{})
+
+func TestLayerOrder(t *testing.T) {
+	m, _ := newMatchWith(t, 1, testDeck(caster))
+
+	c := take(t, m.CurrentPlayer(), caster)
+	put(t, c, match.BATTLEZONE)
+
+	// The multiplier comes first on the card, it is still applied after the bonus
+	c.AddCondition(match.Permanent, double, bonus(1))
+
+	if attack := c.GetAttack(match.NewContext(m, &match.EndTurnEvent{})); attack != 4 {
+		t.Fatalf("the attack is %d instead of (1 + 1) * 2", attack)
+	}
+}
+
+func TestLayerClamp(t *testing.T) {
+	m, _ := newMatchWith(t, 1, testDeck(caster))
+
+	c := take(t, m.CurrentPlayer(), caster)
+	put(t, c, match.BATTLEZONE)
+
+	c.AddCondition(match.Permanent, bonus(200), bonus(200))
+
+	if attack := c.GetAttack(match.NewContext(m, &match.EndTurnEvent{})); attack != 255 {
+		t.Fatalf("the attack is %d instead of 255", attack)
+	}
+}
+
+func TestLayerInvalidation(t *testing.T) {
+	m, _ := newMatchWith(t, 1, testDeck(caster, gaja, villager))
+	p := m.CurrentPlayer()
+
+	c := take(t, p, caster)
+	put(t, c, match.BATTLEZONE)
+	c.AddCondition(match.Permanent, guard)
+
+	ctx := match.NewContext(m, &match.EndTurnEvent{})
+
+	if defence := c.GetDefence(ctx); defence != 2 {
+		t.Fatalf("the untapped card has %d defence instead of 2", defence)
+	}
+
+	c.SetTapped(true)
+
+	if defence := c.GetDefence(ctx); defence != 1 {
+		t.Fatalf("the tapped card has %d defence instead of 1", defence)
+	}
+
+	g := take(t, p, gaja)
+	put(t, g, match.BATTLEZONE)
+
+	v := take(t, p, villager)
+	put(t, v, match.BATTLEZONE)
+
+	if attack := g.GetAttack(ctx); attack != 3 {
+		t.Fatalf("the card has %d attack instead of 3 with a beast beside it", attack)
+	}
+
+	put(t, v, match.GRAVEYARD)
+
+	if attack := g.GetAttack(ctx); attack != 2 {
+		t.Fatalf("the card has %d attack instead of 2 once the beast left", attack)
+	}
+}
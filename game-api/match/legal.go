@@ -0,0 +1,132 @@
+package match
+
+import (
+	"encoding/json"
+
+	"github.com/sirupsen/logrus"
+)
+
+// LegalActions lists what a player can do at the moment
+type LegalActions struct {
+	// Play holds the cards in hand that can be played
+	Play []string `json:"play"`
+	// Set holds the cards in hand that can be set down
+	Set []string `json:"set"`
+	// Attacks holds the creatures that can attack
+	Attacks []LegalAttack `json:"attacks"`
+	// EndTurn is true if the turn can be ended
+	EndTurn bool `json:"endTurn"`
+}
+
+// LegalAttack is a creature that can attack, along with what it can attack
+type LegalAttack struct {
+	ID string `json:"id"`
+	// Player is true if the creature can attack the opponent
+	Player bool `json:"player"`
+	// Targets holds the creatures of the opponent it can attack
+	Targets []string `json:"targets"`
+}
+
+// LegalActions returns what the player can do at the moment. Cards are asked through dry runs of
+// the events the actions would fire, so that an action is only listed if no card interrupts it.
+// Nothing is legal outside the player's turn or while an input is being resolved
+func (m *Match) LegalActions(p *Player) LegalActions {
+	actions := LegalActions{
+		Play:    make([]string, 0),
+		Set:     make([]string, 0),
+		Attacks: make([]LegalAttack, 0),
+	}
+
+	if !m.started || m.ended || m.Paused() || !p.turn || p.busy() {
+		return actions
+	}
+
+	if m.checkPhase("play_card") == nil {
+		for _, c := range p.CollectCards(HAND) {
+			if m.DryRun(&PlayCardEvent{ID: c.id}) {
+				actions.Play = append(actions.Play, c.id)
+			}
+		}
+	}
+
+	if m.checkPhase("set_card") == nil {
+		for _, c := range p.CollectCards(HAND) {
+			actions.Set = append(actions.Set, c.id)
+		}
+	}
+
+	if m.checkPhase("attack_player") == nil && m.canAttack(p) {
+		targets := Filter(m.Opponent(p).CollectCards(BATTLEZONE), func(c *Card) bool { return c.Tapped })
+
+		for _, c := range p.CollectCards(BATTLEZONE) {
+			attack := LegalAttack{
+				ID:      c.id,
+				Player:  m.DryRun(&AttackPlayer{ID: c.id}),
+				Targets: make([]string, 0),
+			}
+
+			for _, target := range targets {
+				if m.DryRun(&AttackCreature{ID: c.id, TargetID: target.id}) {
+					attack.Targets = append(attack.Targets, target.id)
+				}
+			}
+
+			if attack.Player || len(attack.Targets) > 0 {
+				actions.Attacks = append(actions.Attacks, attack)
+			}
+		}
+	}
+
+	actions.EndTurn = m.checkPhase("end_turn") == nil && m.DryRun(&EndTurnEvent{})
+
+	return actions
+}
+
+// legalActions returns the legal actions of the player for the state they are sent. Only the player
+// in which turn it is can act, and their actions are only worked out again once the state changed
+func (p *Player) legalActions(state State) *LegalActions {
+	if !p.turn {
+		return &LegalActions{
+			Play:    make([]string, 0),
+			Set:     make([]string, 0),
+			Attacks: make([]LegalAttack, 0),
+		}
+	}
+
+	// The clocks count down on every update, they don't change what is legal
+	state.TurnTime = 0
+	state.Me.Clock = 0
+	state.Opponent.Clock = 0
+
+	data, err := json.Marshal(struct {
+		State State
+		Busy  bool
+	}{state, p.busy()})
+	if err != nil {
+		logrus.Debug(err)
+		actions := p.match.LegalActions(p)
+		return &actions
+	}
+
+	p.mutex.Lock()
+	if p.actions != nil && p.actionsState == string(data) {
+		actions := p.actions
+		p.mutex.Unlock()
+		return actions
+	}
+	p.mutex.Unlock()
+
+	actions := p.match.LegalActions(p)
+
+	p.mutex.Lock()
+	p.actions = &actions
+	p.actionsState = string(data)
+	p.mutex.Unlock()
+
+	return &actions
+}
+
+// canAttack returns false on the first turn of the player who started, who can't attack yet
+func (m *Match) canAttack(p *Player) bool {
+	return !(p.turnNo == 1 && p == m.player1)
+}
@@ -4,7 +4,10 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"math/rand"
+	"reflect"
 	"sync"
+	"time"
 
 	"github.com/sirupsen/logrus"
 )
@@ -16,28 +19,125 @@ type Match struct {
 
 	mutex *sync.Mutex
 
-	started bool
-
-	winner *Player
-	quit   chan bool
+	seed int64
+	src  *source
+	rand *rand.Rand
+
+	journal      Journal
+	journalMutex *sync.Mutex
+
+	started  bool
+	phase    Phase
+	mulligan Mulligan
+
+	timeControl  TimeControl
+	clockMutex   *sync.Mutex
+	clockRunning *Player
+	clockSince   time.Time
+	turnStarted  time.Time
+	pausedAt     time.Time
+
+	spectators      []Writer
+	spectatorsMutex *sync.Mutex
+
+	stack      []*StackItem
+	stackCount int
+
+	// index holds the subscribers of every event type that was fired since the cards last changed
+	index map[reflect.Type][]subscriber
+	// cache holds the characteristics of every card that was looked at since the cards last changed
+	cache map[*Card]*Stats
+	// uses holds the attack and defence of cards for the events they were used for
+	uses       map[usage]power
+	indexMutex *sync.Mutex
+
+	// resolving counts the effects being handled, a beaten player only loses once none is left
+	resolving int
+	// triggers wait for the effects being handled to be over
+	triggers []func()
+
+	// drawOffer is the player who offered a draw this turn
+	drawOffer *Player
+
+	// headless matches don't send state updates, like clones used to look ahead
+	headless bool
+
+	// checkpoint is the state of the match before the latest input, it is what Snapshot returns
+	// while an input is being resolved
+	checkpoint      *Snapshot
+	checkpointMutex *sync.Mutex
+
+	// inputting is set while an input is being resolved, settling holds the settle inputs that arrived
+	// meanwhile and ending is set once one of them is to end the match
+	inputting  bool
+	settling   []func()
+	ending     bool
+	inputMutex *sync.Mutex
+	inputDone  *sync.Cond
+
+	ended bool
+	// winner is nil if the match was drawn
+	winner   *Player
+	quit     chan bool
+	endMutex *sync.Mutex
+}
+
+// New returns a new match object seeded from the current time
+func New() *Match {
+	return NewWithSeed(time.Now().UnixNano())
 }
 
-// New returns a new match object
-func New() *Match {
+// NewWithSeed returns a new match object whose randomness is entirely derived from seed,
+// so that the same seed and the same inputs always produce the same game
+func NewWithSeed(seed int64) *Match {
+	src := newSource(seed)
+	inputMutex := &sync.Mutex{}
+
 	return &Match{
 		mutex: &sync.Mutex{},
-		quit:  make(chan bool),
+		seed:  seed,
+		src:   src,
+		rand:  newRand(src),
+
+		journal:      Journal{Seed: seed},
+		journalMutex: &sync.Mutex{},
+
+		clockMutex: &sync.Mutex{},
+
+		spectators:      make([]Writer, 0),
+		spectatorsMutex: &sync.Mutex{},
+
+		stack: make([]*StackItem, 0),
+
+		indexMutex: &sync.Mutex{},
+
+		checkpointMutex: &sync.Mutex{},
+
+		inputMutex: inputMutex,
+		inputDone:  sync.NewCond(inputMutex),
+		endMutex:   &sync.Mutex{},
+
+		// Buffered so that ending a match never blocks, even when nobody is listening, like in a replay
+		quit: make(chan bool, 1),
 	}
 }
 
+// Seed returns the seed of the match's random source
+func (m *Match) Seed() int64 {
+	return m.seed
+}
+
+// Rand returns the match's random source, card effects must use this for anything random
+func (m *Match) Rand() *rand.Rand {
+	return m.rand
+}
+
 // PlayerForWriter returns the player for a given writer or an error if the writer is not in  p1 or p2
 func (m *Match) PlayerForWriter(w Writer) (*Player, error) {
-	if m.player1.writer == w {
-		return m.player1, nil
-	}
-
-	if m.player2.writer == w {
-		return m.player2, nil
+	for _, p := range m.Players() {
+		if p.writer == w {
+			return p, nil
+		}
 	}
 
 	return nil, errors.New("not a player of this match")
@@ -53,9 +153,17 @@ func (m *Match) Quit() <-chan bool {
 	return m.quit
 }
 
-// Winner returns true or false based on if the Player is the winner
+// Ended returns true once the match is over
+func (m *Match) Ended() bool {
+	m.endMutex.Lock()
+	defer m.endMutex.Unlock()
+
+	return m.ended
+}
+
+// Winner returns true or false based on if the Player is the winner, nobody is the winner of a drawn match
 func (m *Match) Winner(p *Player) bool {
-	return p == m.winner
+	return p != nil && p == m.winner
 }
 
 // AddPlayer adds a new player
@@ -72,6 +180,10 @@ func (m *Match) AddPlayer(name string, writer Writer) error {
 		return errors.New("players at max capacity")
 	}
 
+	m.journalMutex.Lock()
+	m.journal.Players = append(m.journal.Players, name)
+	m.journalMutex.Unlock()
+
 	return nil
 }
 
@@ -89,11 +201,62 @@ func (m *Match) Parse(w Writer, data []byte) {
 		return
 	}
 
+	m.Input(p, data)
+}
+
+// Input processes the data provided by the given player, it is what Parse does once it knows the player
+func (m *Match) Input(p *Player, data []byte) {
+	defer func() {
+		if r := recover(); r != nil {
+			logrus.Warnf("Recovered from processing input in match. %v", r)
+		}
+	}()
+
+	var msg Message
+	if err := json.Unmarshal(data, &msg); err != nil {
+		return
+	}
+
+	switch msg.Header {
+	case "concede", "offer_draw", "accept_draw", "timeout":
+		m.afterInput(func() { m.settle(p, msg.Header, data) }, m.started && msg.Header != "offer_draw")
+		return
+	}
+
+	// Answers to prompts arrive while the input that raised the prompt is being resolved
+	if msg.Header == "action" || msg.Header == "cancel" {
+		if m.Paused() {
+			Warn(p, "The match is paused until your opponent is back")
+			return
+		}
+
+		m.answerPrompt(p, msg.Header, data)
+		return
+	}
+
 	if p.wait {
 		Warn(p, "Waiting for an action to resolve")
 		return
 	}
 
+	if m.Paused() {
+		Warn(p, "The match is paused until your opponent is back")
+		return
+	}
+
+	if !m.beginInput() {
+		Warn(p, "Waiting for an action to resolve")
+		return
+	}
+
+	// Deferred before anything else so that the settle inputs that arrived meanwhile run last
+	defer m.endInput()
+
+	m.saveCheckpoint()
+
+	// Deferred first so that it is sent once both players are done waiting, listing what they can do next
+	defer m.BroadcastState()
+
 	if m.player1 != nil {
 		m.player1.waiting(true)
 		defer m.player1.waiting(false)
@@ -104,11 +267,13 @@ func (m *Match) Parse(w Writer, data []byte) {
 		defer m.player2.waiting(false)
 	}
 
-	var msg Message
-	if err := json.Unmarshal(data, &msg); err != nil {
+	if err := m.checkPhase(msg.Header); err != nil {
+		Warn(p, err.Error())
 		return
 	}
 
+	// Inputs are journaled once they pass their checks, before they take effect, so that the prompt
+	// answers they lead to come after them. Prompt answers are journaled by Player.Prompt
 	switch msg.Header {
 	case "choose_deck":
 		{
@@ -127,11 +292,14 @@ func (m *Match) Parse(w Writer, data []byte) {
 				return
 			}
 
+			m.record(p, "choose_deck", data)
+
 			m.Start()
 		}
 	case "end_turn":
 		{
 			if m.started && p.turn {
+				m.record(p, "end_turn", data)
 				m.EndTurn()
 			}
 		}
@@ -156,6 +324,8 @@ func (m *Match) Parse(w Writer, data []byte) {
 				return
 			}
 
+			m.record(p, "set_card", data)
+
 			if err := c.MoveCard(TRAPZONE); err != nil {
 				logrus.Debug(err)
 				return
@@ -177,39 +347,10 @@ func (m *Match) Parse(w Writer, data []byte) {
 			}
 
 			if ok := p.HasCard(msg.ID, HAND); ok {
+				m.record(p, "play_card", data)
 				m.PlayCard(msg.ID)
 			}
 		}
-	case "action":
-		{
-			var msg struct {
-				Cards []string `json:"cards"`
-			}
-			if err := json.Unmarshal(data, &msg); err != nil {
-				Warn(p, "Invalid selection")
-				return
-			}
-
-			m := make(map[string]bool)
-			for _, card := range msg.Cards {
-				m[card] = true
-			}
-
-			var cards []string
-			for card := range m {
-				cards = append(cards, card)
-			}
-
-			if p.Action != nil {
-				p.Action <- cards
-			}
-		}
-	case "cancel":
-		{
-			if p.Cancel != nil {
-				p.Cancel <- true
-			}
-		}
 	case "attack_player":
 		{
 			if !m.started || !p.turn {
@@ -220,7 +361,7 @@ func (m *Match) Parse(w Writer, data []byte) {
 				ID string `json:"id"`
 			}
 
-			if p.turnNo == 1 && p == m.player1 {
+			if !m.canAttack(p) {
 				Warn(p, "player 1 can't attack on first turn")
 				return
 			}
@@ -230,6 +371,7 @@ func (m *Match) Parse(w Writer, data []byte) {
 				return
 			}
 
+			m.record(p, "attack_player", data)
 			m.AttackPlayer(p, msg.ID)
 		}
 	case "attack_creature":
@@ -242,7 +384,7 @@ func (m *Match) Parse(w Writer, data []byte) {
 				ID string `json:"id"`
 			}
 
-			if p.turnNo == 1 && p == m.player1 {
+			if !m.canAttack(p) {
 				Warn(p, "player 1 can't attack on first turn")
 				return
 			}
@@ -252,6 +394,7 @@ func (m *Match) Parse(w Writer, data []byte) {
 				return
 			}
 
+			m.record(p, "attack_creature", data)
 			m.AttackCreature(p, msg.ID)
 		}
 	default:
@@ -259,6 +402,44 @@ func (m *Match) Parse(w Writer, data []byte) {
 	}
 }
 
+// answerPrompt hands the selection sent by the player to their open prompt
+func (m *Match) answerPrompt(p *Player, header string, data []byte) {
+	switch header {
+	case "action":
+		{
+			var msg struct {
+				Cards []string `json:"cards"`
+			}
+			if err := json.Unmarshal(data, &msg); err != nil {
+				Warn(p, "Invalid selection")
+				return
+			}
+
+			seen := make(map[string]bool)
+			cards := make([]string, 0)
+
+			for _, card := range msg.Cards {
+				if !seen[card] {
+					seen[card] = true
+					cards = append(cards, card)
+				}
+			}
+
+			decision := Decision{Cards: cards}
+
+			if !p.answer(decision) {
+				Warn(p, "The cards you selected does not meet the requirements")
+			}
+		}
+	case "cancel":
+		{
+			decision := Decision{Cancel: true}
+
+			p.answer(decision)
+		}
+	}
+}
+
 // Opponent returns the opponent of the given player
 func (m *Match) Opponent(p *Player) *Player {
 	if m.player1 == p {
@@ -292,6 +473,7 @@ func (m *Match) Chat(sender string, message string) {
 
 	m.player1.Write(msg)
 	m.player2.Write(msg)
+	m.writeSpectators(msg)
 }
 
 // MessagePlayer sends a chat message from the server to the specified player
@@ -349,29 +531,55 @@ func (m *Match) Destroy(card *Card, source *Card) {
 func (m *Match) HandleFx(ctx *Context) {
 	defer m.BroadcastState()
 
-	cards := make([]*Card, 0)
+	m.resolving++
+	m.handle(ctx)
+	m.resolving--
 
-	// The player in which turn it is is to be handled first
-	if m.player1.turn {
-		cards = append(
-			m.player1.CollectCards(AllContainers()...),
-			m.player2.CollectCards(AllContainers()...)...,
-		)
-	} else {
-		cards = append(
-			m.player2.CollectCards(AllContainers()...),
-			m.player1.CollectCards(AllContainers()...)...,
-		)
+	m.checkLosses()
+
+	for m.resolving == 0 && len(m.triggers) > 0 {
+		if m.ended {
+			m.triggers = nil
+			break
+		}
+
+		trigger := m.triggers[0]
+		m.triggers = m.triggers[1:]
+
+		trigger()
 	}
+}
 
-	for _, c := range cards {
-		for _, h := range append(c.effects, c.conditions...) {
-			if ctx.cancel {
-				break
-			}
+// Trigger runs fn once the effects being handled are over, in the order triggers were queued,
+// or at once if nothing is being handled. Triggers are dropped if the match ends
+func (m *Match) Trigger(fn func()) {
+	if m.ended {
+		return
+	}
+
+	if m.resolving == 0 {
+		fn()
+		return
+	}
 
-			h(c, ctx)
+	m.triggers = append(m.triggers, fn)
+}
+
+// handle runs the handlers of every card for the context and then what they scheduled,
+// without sending a state update. It is used on its own for queries such as GetRank and for dry runs
+func (m *Match) handle(ctx *Context) {
+	// Only the handlers that subscribed to the event are called, the ones of the player in which turn it is first
+	for _, s := range m.subscribers(reflect.TypeOf(ctx.event)) {
+		if ctx.cancel {
+			break
 		}
+
+		s.handler(s.card, ctx)
+	}
+
+	// Nothing that was scheduled runs in a dry run
+	if ctx.dryRun {
+		return
 	}
 
 	for _, h := range ctx.postFxs {
@@ -383,6 +591,17 @@ func (m *Match) HandleFx(ctx *Context) {
 	}
 }
 
+// DryRun returns true if the event would not be interrupted by any card. Only the checks cards make
+// before scheduling their behaviour run, so it is safe to call at any time
+func (m *Match) DryRun(event interface{}) bool {
+	ctx := NewContext(m, event)
+	ctx.dryRun = true
+
+	m.handle(ctx)
+
+	return !ctx.cancel
+}
+
 // BroadcastState sends the current game's state to both players, hiding the opponent's hand
 func (m *Match) BroadcastState() {
 	defer func() {
@@ -392,13 +611,26 @@ func (m *Match) BroadcastState() {
 		}
 	}()
 
+	if m.headless || m.player1 == nil || m.player2 == nil {
+		return
+	}
+
 	player1 := m.player1.denormalized()
 	player2 := m.player2.denormalized()
 
+	var turnTime int64
+	if m.timeControl.Turn > 0 && m.started {
+		turnTime = m.remainingTurn().Milliseconds()
+	}
+
 	p1state := StateMessage{
 		Header: "state_update",
 		State: State{
 			MyTurn:   m.player1.turn,
+			Phase:    m.phase,
+			TurnTime: turnTime,
+			Paused:   m.Paused(),
+			Stack:    m.stackState(),
 			Me:       player1,
 			Opponent: player2,
 		},
@@ -408,11 +640,21 @@ func (m *Match) BroadcastState() {
 		Header: "state_update",
 		State: State{
 			MyTurn:   m.player2.turn,
+			Phase:    m.phase,
+			TurnTime: turnTime,
+			Paused:   m.Paused(),
+			Stack:    m.stackState(),
 			Me:       player2,
 			Opponent: player1,
 		},
 	}
 
+	p1state.Actions = m.player1.legalActions(p1state.State)
+	p2state.Actions = m.player2.legalActions(p2state.State)
+
+	spectatorState := m.spectatorState(player1, player2)
+	spectatorState.State.TurnTime = turnTime
+
 	p1state.State.Opponent.Hand = hideCards(&p1state.State.Opponent.Hand)
 	p1state.State.Opponent.Trapzone = hideCards(&p1state.State.Opponent.Trapzone)
 
@@ -421,34 +663,68 @@ func (m *Match) BroadcastState() {
 
 	m.player1.Write(p1state)
 	m.player2.Write(p2state)
+	m.writeSpectators(spectatorState)
 }
 
-// End ends the match
+// End ends the match, a nil winner draws it. Only the first call ends it
 func (m *Match) End(winner *Player, reason string) {
 	logrus.Debugf("Attempting to end match")
 
-	m.Chat("server", fmt.Sprintf("%s won the match, %s", winner.Name(), reason))
+	m.endMutex.Lock()
+	if m.ended {
+		m.endMutex.Unlock()
+		return
+	}
 
+	m.ended = true
 	m.winner = winner
+	m.endMutex.Unlock()
+
+	if winner != nil {
+		m.Chat("server", fmt.Sprintf("%s won the match, %s", winner.Name(), reason))
+	} else {
+		m.Chat("server", fmt.Sprintf("The match is a draw, %s", reason))
+	}
+
+	m.Chat("Server", fmt.Sprintf("Match seed: %d", m.seed))
 
 	m.quit <- true
 	close(m.quit)
+
+	// Let inputs that were waiting for a selection finish
+	m.answerPrompts()
 }
 
 // NewAction prompts the user to make a selection of the specified []Cards
 func (m *Match) NewAction(p *Player, cards []*Card, minSelections int, maxSelections int, text string, cancellable bool) {
+	prompt := Prompt{
+		Cards:       cards,
+		Text:        text,
+		Min:         minSelections,
+		Max:         maxSelections,
+		Cancellable: cancellable,
+	}
+
+	p.openPrompt(prompt)
+	m.writePrompt(p, prompt)
+}
+
+// writePrompt sends the selection the player has to make to their client
+func (m *Match) writePrompt(p *Player, prompt Prompt) {
 	m.WritePlayer(p, ActionMessage{
 		Header:        "action",
-		Cards:         denormalizeCards(cards),
-		Text:          text,
-		MinSelections: minSelections,
-		MaxSelections: maxSelections,
-		Cancellable:   cancellable,
+		Cards:         denormalizeCards(prompt.Cards),
+		Text:          prompt.Text,
+		MinSelections: prompt.Min,
+		MaxSelections: prompt.Max,
+		Cancellable:   prompt.Cancellable,
 	})
 }
 
 // CloseAction closes the card selection popup for the given player
 func (m *Match) CloseAction(p *Player) {
+	p.closePrompt()
+
 	m.WritePlayer(p, Message{
 		Header: "close_action",
 	})
@@ -468,12 +744,15 @@ func (m *Match) Highlight(ids ...string) {
 	msg := HighlightMessage{"highlight", append(make([]string, 0), ids...)}
 	m.player1.Write(msg)
 	m.player2.Write(msg)
+	m.writeSpectators(msg)
 }
 
 // changeCurrentPlayer changes the current player
 func (m *Match) changeCurrentPlayer() {
 	m.player1.turn = !m.player1.turn
 	m.player2.turn = !m.player2.turn
+
+	m.invalidate()
 }
 
 // Start starts the match
@@ -485,10 +764,12 @@ func (m *Match) Start() {
 	}
 
 	m.mutex.Lock()
-	defer m.mutex.Unlock()
 
 	m.started = true
 
+	m.player1.clock = m.timeControl.Clock
+	m.player2.clock = m.timeControl.Clock
+
 	m.player1.ShuffleDeck()
 	m.player2.ShuffleDeck()
 
@@ -497,6 +778,14 @@ func (m *Match) Start() {
 
 	m.Chat("Server", "The match has begun!")
 
+	// The seed gives away both decks and hands, it is only shown once the match is over
+	logrus.Debugf("Started match with seed %d", m.seed)
+
+	// Players may take a while to choose their mulligan, the seats must not be locked meanwhile
+	m.mutex.Unlock()
+
+	m.mulliganStep()
+
 	// This is done to offset beginNewTurn which changes current player
 	m.changeCurrentPlayer()
 
@@ -508,6 +797,11 @@ func (m *Match) beginNewTurn() {
 	m.changeCurrentPlayer()
 	m.CurrentPlayer().turnNo++
 
+	m.drawOffer = nil
+
+	m.startTurnClock()
+
+	m.setPhase(BeginPhase)
 	m.HandleFx(NewContext(m, &BeginTurnStep{}))
 
 	m.untapStep()
@@ -515,25 +809,36 @@ func (m *Match) beginNewTurn() {
 
 // untapStep ...
 func (m *Match) untapStep() {
+	m.setPhase(UntapPhase)
+	m.CurrentPlayer().expireConditions(NextTurn)
 	m.HandleFx(NewContext(m, &UntapStep{}))
 	m.startOfTurnStep()
 }
 
 // startOfTurnStep ...
 func (m *Match) startOfTurnStep() {
+	m.setPhase(StartPhase)
 	m.HandleFx(NewContext(m, &StartOfTurnStep{}))
 	m.drawStep()
 }
 
 // drawStep ...
 func (m *Match) drawStep() {
+	m.setPhase(DrawPhase)
 	m.HandleFx(NewContext(m, &DrawStep{}))
 	m.CurrentPlayer().DrawCards(1)
+
+	m.setPhase(MainPhase)
 }
 
 // endStep ...
 func (m *Match) endStep() {
+	m.setPhase(EndPhase)
 	m.HandleFx(NewContext(m, &EndStep{}))
+
+	m.player1.expireConditions(EndOfTurn)
+	m.player2.expireConditions(EndOfTurn)
+
 	m.beginNewTurn()
 }
 
@@ -583,11 +888,11 @@ func (m *Match) SpellCast(id string, targets []*Card) {
 
 // AttackPlayer is called when the player attempts to attack the opposing player
 func (m *Match) AttackPlayer(p *Player, id string) {
+	m.setPhase(BattlePhase)
+
 	m.Highlight(id)
 	defer m.Highlight()
 
-	m.Opponent(p).waiting(false)
-
 	ctx := NewContext(m, &AttackPlayer{
 		ID: id,
 	})
@@ -599,7 +904,7 @@ func (m *Match) AttackPlayer(p *Player, id string) {
 	if err != nil {
 		logrus.Debug(err)
 	} else {
-		card.Tapped = true
+		card.SetTapped(true)
 	}
 
 	m.BroadcastState()
@@ -615,12 +920,14 @@ func (m *Match) AttackCreature(p *Player, id string) {
 		true,
 	)
 
-	if len(targets) > 0 {
-		m.Highlight(id, targets[0].id)
-		defer m.Highlight()
+	if len(targets) < 1 {
+		return
 	}
 
-	m.Opponent(p).waiting(false)
+	m.setPhase(BattlePhase)
+
+	m.Highlight(id, targets[0].id)
+	defer m.Highlight()
 
 	ctx := NewContext(m, &AttackCreature{
 		ID:       id,
@@ -634,7 +941,7 @@ func (m *Match) AttackCreature(p *Player, id string) {
 	if err != nil {
 		logrus.Debug(err)
 	} else {
-		card.Tapped = true
+		card.SetTapped(true)
 	}
 
 	m.BroadcastState()
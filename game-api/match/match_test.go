@@ -0,0 +1,141 @@
+package match_test
+
+import (
+	"encoding/json"
+	"math/rand"
+	"testing"
+
+	"github.com/jyotiskaghosh/ganjifa/game-api/cards"
+	"github.com/jyotiskaghosh/ganjifa/game-api/match"
+)
+
+func init() {
+	for _, set := range cards.Sets {
+		for uid, ctor := range *set {
+			match.AddCard(uid, ctor)
+		}
+	}
+}
+
+// recorder is the writer of a player in a test, it keeps the state updates the player receives
+type recorder struct {
+	states []match.StateMessage
+}
+
+func (r *recorder) Write(msg interface{}) {
+	if state, ok := msg.(match.StateMessage); ok {
+		r.states = append(r.states, state)
+	}
+}
+
+// newMatch returns a started match between two players who answer every prompt with the default
+func newMatch(t *testing.T, seed int64) (*match.Match, []*recorder) {
+	deck := make([]int, 0)
+	for i := 0; i < 40; i++ {
+		deck = append(deck, i)
+	}
+
+	return newMatchWith(t, seed, deck)
+}
+
+// newMatchWith returns a started match like newMatch, where both players play the given deck
+func newMatchWith(t *testing.T, seed int64, deck []int) (*match.Match, []*recorder) {
+	m := match.NewWithSeed(seed)
+	writers := []*recorder{{}, {}}
+
+	for i, name := range []string{"a", "b"} {
+		if err := m.AddPlayer(name, writers[i]); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, p := range m.Players() {
+		p.SetDecider(match.DeciderFunc(func(p *match.Player, prompt match.Prompt) match.Decision {
+			return match.DefaultDecision(prompt)
+		}))
+	}
+
+	for _, p := range m.Players() {
+		input(m, p, map[string]interface{}{"header": "choose_deck", "cards": deck})
+	}
+
+	if !m.Started() {
+		t.Fatal("the match did not start")
+	}
+
+	return m, writers
+}
+
+// input sends an input of the player to the match
+func input(m *match.Match, p *match.Player, msg map[string]interface{}) {
+	data, _ := json.Marshal(msg)
+	m.Input(p, data)
+}
+
+// move makes the turn player take one of their legal actions at random
+func move(m *match.Match, rng *rand.Rand) {
+	p := m.CurrentPlayer()
+	actions := m.LegalActions(p)
+
+	switch n := rng.Intn(10); {
+	case n < 4 && len(actions.Play) > 0:
+		input(m, p, map[string]interface{}{"header": "play_card", "id": actions.Play[rng.Intn(len(actions.Play))]})
+	case n < 5 && len(actions.Set) > 0:
+		input(m, p, map[string]interface{}{"header": "set_card", "id": actions.Set[rng.Intn(len(actions.Set))]})
+	case n < 8 && len(actions.Attacks) > 0:
+		attack := actions.Attacks[rng.Intn(len(actions.Attacks))]
+
+		if attack.Player {
+			input(m, p, map[string]interface{}{"header": "attack_player", "id": attack.ID})
+		} else {
+			input(m, p, map[string]interface{}{"header": "attack_creature", "id": attack.ID})
+		}
+	default:
+		input(m, p, map[string]interface{}{"header": "end_turn"})
+	}
+}
+
+// play makes n moves, or less if the match ends
+func play(m *match.Match, seed int64, n int) {
+	rng := rand.New(rand.NewSource(seed))
+
+	for i := 0; i < n && !m.Ended(); i++ {
+		move(m, rng)
+	}
+}
+
+// testDeck returns a legal deck of 4 copies of each of the given cards, filled up with the first set
+func testDeck(cards ...int) []int {
+	deck := make([]int, 0)
+
+	for _, c := range cards {
+		for i := 0; i < 4; i++ {
+			deck = append(deck, c)
+		}
+	}
+
+	for i := 0; len(deck) < 40; i++ {
+		deck = append(deck, i)
+	}
+
+	return deck
+}
+
+// take returns a card of the player with the given card id that is in their hand or deck
+func take(t *testing.T, p *match.Player, cardID int) *match.Card {
+	for _, c := range p.CollectCards(match.HAND, match.DECK) {
+		if c.CardID() == cardID {
+			return c
+		}
+	}
+
+	t.Fatalf("%s has no card %d left", p.Name(), cardID)
+	return nil
+}
+
+// put moves the card to the container
+func put(t *testing.T, c *match.Card, container match.Container) {
+	if err := c.MoveCard(container); err != nil {
+		t.Fatal(err)
+	}
+}
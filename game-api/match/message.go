@@ -24,25 +24,51 @@ type CardState struct {
 	ID            string           `json:"id"`
 	UID           int              `json:"uid"`
 	Name          string           `json:"name"`
+	Text          string           `json:"text"`
+	Keywords      []string         `json:"keywords"`
 	Civ           civ.Civilisation `json:"civilization"`
 	Tapped        bool             `json:"tapped"`
 	FaceDown      bool             `json:"faceDown"`
+	Token         bool             `json:"token"`
+	Counters      map[string]int   `json:"counters"`
 	AttachedCards []CardState      `json:"attachedCards"`
 }
 
 // PlayerState stores information about the state of the current player
 type PlayerState struct {
 	Life       int         `json:"life"`
+	Clock      int64       `json:"clock"`
 	Deck       int         `json:"deck"`
 	Hand       []CardState `json:"hand"`
 	Graveyard  []CardState `json:"graveyard"`
 	Battlezone []CardState `json:"battlezone"`
 	Trapzone   []CardState `json:"trapzone"`
+
+	// DrawOffer is true while the player's draw offer is open
+	DrawOffer bool `json:"drawOffer"`
+}
+
+// StackItemState stores information about an item on the stack
+type StackItemState struct {
+	ID     string    `json:"id"`
+	Card   CardState `json:"card"`
+	Player string    `json:"player"`
+	Text   string    `json:"text"`
 }
 
 // State stores information about the current state of the match in the eyes of a given player
 type State struct {
-	MyTurn   bool        `json:"myTurn"`
+	MyTurn   bool  `json:"myTurn"`
+	Phase    Phase `json:"phase"`
+	TurnTime int64 `json:"turnTime"`
+	Paused   bool  `json:"paused"`
+
+	// Stack holds the items waiting to resolve, the last one resolves first
+	Stack []StackItemState `json:"stack"`
+
+	// Spectating is set in the state sent to spectators, where both hands and trap zones are hidden
+	Spectating bool `json:"spectating"`
+
 	Me       PlayerState `json:"me"`
 	Opponent PlayerState `json:"opponent"`
 }
@@ -51,6 +77,9 @@ type State struct {
 type StateMessage struct {
 	Header string `json:"header"`
 	State  State  `json:"state"`
+
+	// Actions lists what the player can do next, it is left out for spectators
+	Actions *LegalActions `json:"actions,omitempty"`
 }
 
 // ActionMessage is used to prompt the user to make a selection of the specified cards
@@ -0,0 +1,98 @@
+package match
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Mulligan is the rule for redrawing opening hands before the first turn
+type Mulligan struct {
+	// Enabled lets each player put back any part of their opening hand and draw again
+	Enabled bool `json:"enabled"`
+	// Penalty is how many cards fewer than they put back a player draws
+	Penalty int `json:"penalty"`
+}
+
+// SetMulligan sets the mulligan rule of a match that has not started yet
+func (m *Match) SetMulligan(rule Mulligan) error {
+	if m.started {
+		return errors.New("can't change the mulligan rule of a match that has started")
+	}
+
+	if err := rule.Validate(); err != nil {
+		return err
+	}
+
+	m.mulligan = rule
+
+	m.journalMutex.Lock()
+	m.journal.Mulligan = rule
+	m.journalMutex.Unlock()
+
+	return nil
+}
+
+// Validate returns an error if the mulligan rule can't be used
+func (rule Mulligan) Validate() error {
+	if rule.Penalty < 0 {
+		return errors.New("the mulligan penalty can't be negative")
+	}
+
+	return nil
+}
+
+// Mulligan returns the mulligan rule of the match
+func (m *Match) Mulligan() Mulligan {
+	return m.mulligan
+}
+
+// mulliganStep lets both players put back part of their opening hand. Both choose before any hand
+// changes, so neither can tell what the other put back when making their own choice
+func (m *Match) mulliganStep() {
+	if !m.mulligan.Enabled {
+		return
+	}
+
+	m.setPhase(MulliganPhase)
+
+	text := "Select the cards you want to put back into your deck"
+	if m.mulligan.Penalty > 0 {
+		text = fmt.Sprintf("%s, you will draw %d fewer", text, m.mulligan.Penalty)
+	}
+
+	returned := make(map[*Player][]*Card)
+
+	for _, p := range m.Players() {
+		hand := p.CollectCards(HAND)
+		returned[p] = p.Search(hand, text, 0, len(hand), true)
+	}
+
+	for _, p := range m.Players() {
+		cards := returned[p]
+		if len(cards) < 1 {
+			continue
+		}
+
+		for _, c := range cards {
+			if err := c.MoveCard(DECK); err != nil {
+				logrus.Debug(err)
+			}
+		}
+
+		p.ShuffleDeck()
+
+		n := len(cards) - m.mulligan.Penalty
+		if n > 0 {
+			p.DrawCards(n)
+		}
+
+		// Only the player is told, the opponent and spectators only see the hand sizes
+		p.Write(ChatMessage{
+			Header:  "chat",
+			Message: fmt.Sprintf("You put back %d cards", len(cards)),
+			Sender:  "server",
+		})
+	}
+}
@@ -0,0 +1,68 @@
+package match
+
+import (
+	"fmt"
+)
+
+// Phase is a phase of a turn, cards can hook into phase transitions by name through PhaseChanged
+type Phase string
+
+// Phases of a turn, in the order they happen
+const (
+	// MulliganPhase comes once before the first turn, while the players choose their mulligan
+	MulliganPhase Phase = "mulligan"
+
+	BeginPhase  Phase = "begin"
+	UntapPhase  Phase = "untap"
+	StartPhase  Phase = "start"
+	DrawPhase   Phase = "draw"
+	MainPhase   Phase = "main"
+	BattlePhase Phase = "battle"
+	EndPhase    Phase = "end"
+)
+
+// allowedPhases holds the phases in which the turn player may send each header,
+// headers that are not listed are not tied to a phase
+var allowedPhases = map[string][]Phase{
+	"set_card":        {MainPhase},
+	"play_card":       {MainPhase},
+	"attack_player":   {MainPhase, BattlePhase},
+	"attack_creature": {MainPhase, BattlePhase},
+	"end_turn":        {MainPhase, BattlePhase},
+}
+
+// Phase returns the current phase of the turn, it is empty until the match has started
+func (m *Match) Phase() Phase {
+	return m.phase
+}
+
+// setPhase moves the turn to the given phase and fires PhaseChanged
+func (m *Match) setPhase(phase Phase) {
+	if m.phase == phase {
+		return
+	}
+
+	previous := m.phase
+	m.phase = phase
+
+	m.HandleFx(NewContext(m, &PhaseChanged{
+		Phase:    phase,
+		Previous: previous,
+	}))
+}
+
+// checkPhase returns an error if the header can't be sent in the current phase
+func (m *Match) checkPhase(header string) error {
+	phases, ok := allowedPhases[header]
+	if !ok || !m.started {
+		return nil
+	}
+
+	for _, phase := range phases {
+		if m.phase == phase {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("%s is not allowed in the %s phase", header, m.phase)
+}
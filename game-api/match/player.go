@@ -1,10 +1,11 @@
 package match
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
-	"math/rand"
 	"sync"
+	"time"
 
 	"github.com/sirupsen/logrus"
 )
@@ -30,6 +31,9 @@ type Writer interface {
 	Write(interface{})
 }
 
+// maxCopies is how many copies of a card a deck may hold
+const maxCopies = 4
+
 // Player holds the player data
 type Player struct {
 	name   string
@@ -48,13 +52,23 @@ type Player struct {
 	turn   bool
 	turnNo uint8
 
-	Action chan []string
-	Cancel chan bool
+	decider      Decider
+	prompt       *Prompt
+	promptOpened time.Time
+
+	clock time.Duration
 
 	match *Match
 
 	wait  bool
 	mutex *sync.Mutex
+
+	// lost holds why the player was beaten, until the match ends
+	lost string
+
+	// actions holds the legal actions of the player while the state they were worked out for is seen
+	actions      *LegalActions
+	actionsState string
 }
 
 // newPlayer returns a new player
@@ -70,6 +84,7 @@ func newPlayer(name string, writer Writer, match *Match, turn bool) *Player {
 		soul:       make([]*Card, 0),
 		life:       LIFE,
 		turn:       turn,
+		decider:    NewSocketDecider(),
 		match:      match,
 		mutex:      &sync.Mutex{},
 	}
@@ -85,6 +100,16 @@ func (p *Player) Write(msg interface{}) {
 	p.writer.Write(msg)
 }
 
+// Ready returns true once the player has chosen a valid deck
+func (p *Player) Ready() bool {
+	return p.ready
+}
+
+// Life returns the player's life
+func (p *Player) Life() int {
+	return p.life
+}
+
 // IsPlayerTurn is it the Player's turnNo
 func (p *Player) IsPlayerTurn() bool {
 	return p.turn
@@ -95,6 +120,33 @@ func (p *Player) Turn() uint8 {
 	return p.turnNo
 }
 
+// Decider returns the decider that answers the player's prompts
+func (p *Player) Decider() Decider {
+	return p.decider
+}
+
+// SetDecider changes who answers the player's prompts
+func (p *Player) SetDecider(d Decider) {
+	p.decider = d
+}
+
+// answer hands a selection to the player's decider if it answers their open prompt, under the player's mutex
+// so that the prompt can't be closed or replaced meanwhile
+func (p *Player) answer(decision Decision) bool {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if p.prompt == nil || !p.prompt.accepts(decision) {
+		return false
+	}
+
+	if d, ok := p.decider.(answerer); ok {
+		d.Answer(decision)
+	}
+
+	return true
+}
+
 // waiting assigns bool value to m.wait
 func (p *Player) waiting(b bool) {
 	p.mutex.Lock()
@@ -103,8 +155,54 @@ func (p *Player) waiting(b bool) {
 	p.wait = b
 }
 
-// containerRef returns a pointer to one of the player's card zones based on the specified string
+// openPrompt remembers the selection the player is asked to make
+func (p *Player) openPrompt(prompt Prompt) {
+	p.mutex.Lock()
+	p.prompt = &prompt
+	p.promptOpened = time.Now()
+	p.discard()
+	p.mutex.Unlock()
+
+	// The time it takes to answer is on the player's clock
+	p.match.runClock(p)
+}
+
+// closePrompt forgets the player's open selection
+func (p *Player) closePrompt() {
+	p.mutex.Lock()
+	p.prompt = nil
+	p.discard()
+	p.mutex.Unlock()
+
+	p.match.runClock(p.match.CurrentPlayer())
+}
+
+// openedPrompt returns a copy of the player's open selection and when it was opened, or nil
+func (p *Player) openedPrompt() (*Prompt, time.Time) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if p.prompt == nil {
+		return nil, p.promptOpened
+	}
+
+	prompt := *p.prompt
+
+	return &prompt, p.promptOpened
+}
+
+// discard drops an answer left over from another prompt, the player's mutex must be held
+func (p *Player) discard() {
+	if d, ok := p.decider.(answerer); ok {
+		d.discard()
+	}
+}
+
+// containerRef returns a pointer to one of the player's card zones based on the specified string.
+// It is used to change the zone, so the subscribers kept by the match are dropped
 func (p *Player) containerRef(c Container) (*[]*Card, error) {
+	p.match.invalidate()
+
 	switch c {
 	case DECK:
 		return &p.deck, nil
@@ -172,7 +270,7 @@ func (p *Player) createDeck(cards []int) error {
 
 	for _, card := range deck {
 		count[card.cardID]++
-		if count[card.cardID] > 4 {
+		if count[card.cardID] > maxCopies {
 			return errors.New("deck must have only 4 copies of a card")
 		}
 	}
@@ -180,12 +278,15 @@ func (p *Player) createDeck(cards []int) error {
 	p.deck = deck
 	p.ready = true
 
+	p.match.invalidate()
+
 	return nil
 }
 
 // ShuffleDeck randomizes the order of cards in the players deck
 func (p *Player) ShuffleDeck() {
-	rand.Shuffle(len(p.deck), func(i, j int) { p.deck[i], p.deck[j] = p.deck[j], p.deck[i] })
+	p.match.rand.Shuffle(len(p.deck), func(i, j int) { p.deck[i], p.deck[j] = p.deck[j], p.deck[i] })
+	p.match.invalidate()
 	p.match.Chat("Server", fmt.Sprintf("%s's deck was shuffled", p.Name()))
 }
 
@@ -224,7 +325,7 @@ func (p *Player) DrawCards(n int) {
 	}
 
 	if len(p.deck) == 0 {
-		p.match.End(p.match.Opponent(p), fmt.Sprintf("%s has no cards left in his deck", p.Name()))
+		p.lose(fmt.Sprintf("%s has no cards left in his deck", p.Name()))
 	}
 }
 
@@ -273,7 +374,7 @@ func (p *Player) Damage(source *Card, ctx *Context, health uint8) {
 	ctx.match.HandleFx(ctx)
 
 	if p.life <= 0 {
-		ctx.match.End(p.match.Opponent(p), fmt.Sprintf("%s has no life left", p.Name()))
+		p.lose(fmt.Sprintf("%s has no life left", p.Name()))
 	}
 }
 
@@ -298,58 +399,98 @@ func (p *Player) Heal(source *Card, ctx *Context, health uint8) {
 
 // Search prompts the user to select n cards from a slice of cards
 func (p *Player) Search(cards []*Card, text string, min int, max int, cancellable bool) []*Card {
-	p.Action = make(chan []string)
-	p.Cancel = make(chan bool)
-
-	defer close(p.Cancel)
-	defer close(p.Action)
+	result, _ := p.Prompt(Prompt{
+		Cards:       cards,
+		Text:        text,
+		Min:         min,
+		Max:         max,
+		Cancellable: cancellable,
+	})
 
-	p.waiting(false)
+	return result
+}
 
+// Prompt asks the player's decider to make a selection until it gives a valid answer,
+// the returned bool is true if the prompt was cancelled
+func (p *Player) Prompt(prompt Prompt) ([]*Card, bool) {
 	result := make([]*Card, 0)
 
-	if len(cards) < 1 {
-		return result
+	if len(prompt.Cards) < 1 {
+		return result, false
+	}
+
+	// Nobody is asked once the match is being ended, the input only has to finish
+	if p.match.closing() {
+		return p.decide(prompt, DefaultDecision(prompt))
 	}
 
-	p.match.NewAction(p, cards, min, max, text, cancellable)
+	p.match.NewAction(p, prompt.Cards, prompt.Min, prompt.Max, prompt.Text, prompt.Cancellable)
 	defer p.match.CloseAction(p)
 
-	for {
-		select {
-		case Action := <-p.Action:
-			{
-				if len(Action) < min || len(Action) > max || !AssertCardsIn(cards, Action...) {
-					p.match.WarnPlayer(p, "The cards you selected does not meet the requirements")
-					continue
-				}
-				for _, id := range Action {
-					c, err := GetCard(id, cards)
-					if err != nil {
-						logrus.Debugf("Search: %s", err)
-						return result
-					}
-
-					result = append(result, c)
-				}
-				return result
-			}
-		case Cancel := <-p.Cancel:
-			if cancellable && Cancel {
-				return result
-			}
+	decision := p.decider.Decide(p, prompt)
+
+	for !prompt.accepts(decision) {
+		// Programmatic deciders get no second chance, as they would most likely answer the same again
+		if _, ok := p.decider.(answerer); !ok {
+			logrus.Debugf("Prompt: invalid decision %v, using the default", decision)
+			decision = DefaultDecision(prompt)
+			break
 		}
+
+		p.match.WarnPlayer(p, "The cards you selected does not meet the requirements")
+		decision = p.decider.Decide(p, prompt)
 	}
+
+	return p.decide(prompt, decision)
+}
+
+// decide returns the cards of the prompt the decision selected and journals it
+func (p *Player) decide(prompt Prompt, decision Decision) ([]*Card, bool) {
+	result := make([]*Card, 0)
+
+	if decision.Cancel {
+		p.match.record(p, "cancel", []byte(`{"header":"cancel"}`))
+		return result, true
+	}
+
+	for _, id := range decision.Cards {
+		c, err := GetCard(id, prompt.Cards)
+		if err != nil {
+			logrus.Debugf("Prompt: %s", err)
+			return result, false
+		}
+
+		result = append(result, c)
+	}
+
+	answer, err := json.Marshal(struct {
+		Header string   `json:"header"`
+		Cards  []string `json:"cards"`
+	}{"action", decision.Cards})
+	if err != nil {
+		logrus.Debug(err)
+	}
+
+	p.match.record(p, "action", answer)
+
+	return result, false
 }
 
 // denormalized returns a server.PlayerState
 func (p *Player) denormalized() PlayerState {
+	var clock int64
+	if p.match.timeControl.Clock > 0 {
+		clock = p.match.remainingClock(p).Milliseconds()
+	}
+
 	return PlayerState{
 		Life:       p.life,
+		Clock:      clock,
 		Deck:       len(p.deck),
 		Hand:       denormalizeCards(p.hand),
 		Graveyard:  denormalizeCards(p.graveyard),
 		Battlezone: denormalizeCards(p.battlezone),
 		Trapzone:   denormalizeCards(p.trapzone),
+		DrawOffer:  p.match.drawOffer == p,
 	}
 }
@@ -0,0 +1,218 @@
+package match
+
+import (
+	"encoding/json"
+	"errors"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Frame is the result of replaying a single step of a journal, that is a player input
+// together with the prompt answers it consumed
+type Frame struct {
+	Entries []JournalEntry
+	Turn    int
+
+	// States holds the state updates each player received during the step
+	States [2][]StateMessage
+}
+
+// Replay re-simulates a match from its journal one step at a time
+type Replay struct {
+	journal Journal
+	match   *Match
+	writers [2]*replayWriter
+
+	frames []*Frame
+	cursor int
+
+	next  int
+	frame *Frame
+}
+
+// replayWriter is the headless writer of a replayed player
+type replayWriter struct {
+	replay *Replay
+	player int
+}
+
+// Write collects state updates
+func (w *replayWriter) Write(msg interface{}) {
+	if msg, ok := msg.(StateMessage); ok && w.replay.frame != nil {
+		w.replay.frame.States[w.player] = append(w.replay.frame.States[w.player], msg)
+	}
+}
+
+// NewReplay rebuilds the match described by the journal, ready to be stepped through
+func NewReplay(journal Journal) (*Replay, error) {
+	if len(journal.Players) != 2 {
+		return nil, errors.New("journal must have exactly 2 players")
+	}
+
+	r := &Replay{
+		journal: journal,
+		match:   NewWithSeed(journal.Seed),
+		frames:  make([]*Frame, 0),
+		cursor:  -1,
+	}
+
+	if err := r.match.SetMulligan(journal.Mulligan); err != nil {
+		return nil, err
+	}
+
+	for i, name := range journal.Players {
+		r.writers[i] = &replayWriter{replay: r, player: i}
+
+		if err := r.match.AddPlayer(name, r.writers[i]); err != nil {
+			return nil, err
+		}
+	}
+
+	r.match.player1.SetDecider(DeciderFunc(r.decide))
+	r.match.player2.SetDecider(DeciderFunc(r.decide))
+
+	return r, nil
+}
+
+// Match returns the replayed match
+func (r *Replay) Match() *Match {
+	return r.match
+}
+
+// Cursor returns the index of the current step, or -1 before the first step
+func (r *Replay) Cursor() int {
+	return r.cursor
+}
+
+// Frame returns the current frame, or nil before the first step
+func (r *Replay) Frame() *Frame {
+	if r.cursor < 0 {
+		return nil
+	}
+
+	return r.frames[r.cursor]
+}
+
+// State returns the latest state update the given player (1 or 2) had received at the current step
+func (r *Replay) State(player int) (StateMessage, bool) {
+	for i := r.cursor; i >= 0; i-- {
+		if states := r.frames[i].States[player-1]; len(states) > 0 {
+			return states[len(states)-1], true
+		}
+	}
+
+	return StateMessage{}, false
+}
+
+// Next moves the cursor to the next step, simulating it if it has not been replayed yet
+func (r *Replay) Next() bool {
+	if r.cursor+1 < len(r.frames) {
+		r.cursor++
+		return true
+	}
+
+	if r.next >= len(r.journal.Entries) {
+		return false
+	}
+
+	r.step()
+	r.cursor++
+
+	return true
+}
+
+// Previous moves the cursor back to the previous step
+func (r *Replay) Previous() bool {
+	if r.cursor < 0 {
+		return false
+	}
+
+	r.cursor--
+
+	return true
+}
+
+// JumpToTurn moves the cursor to the step that started turn n, counting both players' turns
+func (r *Replay) JumpToTurn(n int) bool {
+	for i, frame := range r.frames {
+		if frame.Turn >= n {
+			r.cursor = i
+			return true
+		}
+	}
+
+	r.cursor = len(r.frames) - 1
+
+	for r.Next() {
+		if r.frames[r.cursor].Turn >= n {
+			return true
+		}
+	}
+
+	return false
+}
+
+// step feeds the next journal entry to the match, prompts raised on the way are answered
+// from the entries that follow it
+func (r *Replay) step() {
+	r.frame = &Frame{}
+
+	r.apply(r.journal.Entries[r.next])
+
+	r.frame.Turn = int(r.match.player1.turnNo) + int(r.match.player2.turnNo)
+	r.frames = append(r.frames, r.frame)
+	r.frame = nil
+}
+
+// apply feeds a single journal entry to the match
+func (r *Replay) apply(e JournalEntry) {
+	r.next++
+	r.frame.Entries = append(r.frame.Entries, e)
+
+	switch e.Header {
+	case "action", "cancel":
+		logrus.Debugf("Replay: skipping unprompted answer %d", e.Seq)
+	default:
+		r.match.Parse(r.writers[e.Player-1], e.Data)
+	}
+}
+
+// decide answers a prompt with the next answer of the prompted player in the journal.
+// Inputs that arrived while the player was thinking are applied first, the way they were in the live match
+func (r *Replay) decide(p *Player, prompt Prompt) Decision {
+	player := 1
+	if p == r.match.player2 {
+		player = 2
+	}
+
+	for r.next < len(r.journal.Entries) {
+		e := r.journal.Entries[r.next]
+
+		switch {
+		case e.Header == "cancel" && e.Player == player:
+			r.next++
+			r.frame.Entries = append(r.frame.Entries, e)
+
+			return Decision{Cancel: true}
+		case e.Header == "action" && e.Player == player:
+			r.next++
+			r.frame.Entries = append(r.frame.Entries, e)
+
+			var msg struct {
+				Cards []string `json:"cards"`
+			}
+
+			if err := json.Unmarshal(e.Data, &msg); err != nil {
+				logrus.Debugf("Replay: %s", err)
+				return DefaultDecision(prompt)
+			}
+
+			return Decision{Cards: msg.Cards}
+		default:
+			r.apply(e)
+		}
+	}
+
+	// The live match ended while this prompt was open
+	return DefaultDecision(prompt)
+}
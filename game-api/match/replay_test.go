@@ -0,0 +1,135 @@
+package match_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/jyotiskaghosh/ganjifa/game-api/match"
+)
+
+// replay steps through the whole journal and returns every frame
+func replay(t *testing.T, journal match.Journal) (*match.Replay, []*match.Frame) {
+	r, err := match.NewReplay(journal)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	frames := make([]*match.Frame, 0)
+
+	for r.Next() {
+		frames = append(frames, r.Frame())
+
+		turns := 0
+		for _, p := range r.Match().Players() {
+			turns += int(p.Turn())
+		}
+
+		if r.Frame().Turn != turns {
+			t.Fatalf("frame %d is at turn %d, the players took %d turns", r.Cursor(), r.Frame().Turn, turns)
+		}
+	}
+
+	return r, frames
+}
+
+// sent returns the states the player received over the frames, in order
+func sent(frames []*match.Frame, player int) []match.StateMessage {
+	result := make([]match.StateMessage, 0)
+
+	for _, frame := range frames {
+		result = append(result, frame.States[player]...)
+	}
+
+	return result
+}
+
+func TestReplayDeterminism(t *testing.T) {
+	m, writers := newMatch(t, 3)
+	play(m, 3, 200)
+
+	journal := m.Journal()
+
+	r, frames := replay(t, journal)
+	_, again := replay(t, journal)
+
+	if r.Match().Ended() != m.Ended() {
+		t.Fatalf("the replay ended %v, the match ended %v", r.Match().Ended(), m.Ended())
+	}
+
+	for i, w := range writers {
+		states := sent(frames, i)
+
+		if len(states) != len(w.states) {
+			t.Fatalf("player %d received %d states in the match and %d in the replay", i+1, len(w.states), len(states))
+		}
+
+		for j := range states {
+			if !reflect.DeepEqual(states[j], w.states[j]) {
+				t.Fatalf("state %d of player %d differs between the match and the replay", j, i+1)
+			}
+		}
+
+		if !reflect.DeepEqual(sent(again, i), states) {
+			t.Fatalf("replaying the same journal twice sent player %d different states", i+1)
+		}
+	}
+}
+
+func TestReplayCursor(t *testing.T) {
+	m, _ := newMatch(t, 3)
+	play(m, 3, 200)
+
+	r, frames := replay(t, m.Journal())
+	last := len(frames) - 1
+
+	if r.Next() {
+		t.Fatal("the replay went past the end of the journal")
+	}
+
+	if !r.Previous() || r.Cursor() != last-1 || r.Frame() != frames[last-1] {
+		t.Fatalf("stepping back from %d went to %d", last, r.Cursor())
+	}
+
+	state, ok := r.State(1)
+	if want := sent(frames[:last], 0); !ok || !reflect.DeepEqual(state, want[len(want)-1]) {
+		t.Fatal("the state after stepping back is not the one sent at that step")
+	}
+
+	if !r.Next() || r.Frame() != frames[last] {
+		t.Fatal("stepping forward again didn't return the frame that was replayed")
+	}
+
+	for r.Cursor() >= 0 {
+		r.Previous()
+	}
+
+	if r.Frame() != nil || r.Previous() {
+		t.Fatal("the replay went back past its start")
+	}
+
+	turn := frames[last].Turn / 2
+
+	if !r.JumpToTurn(turn) {
+		t.Fatalf("couldn't jump to turn %d", turn)
+	}
+
+	if r.Frame().Turn < turn || (r.Cursor() > 0 && frames[r.Cursor()-1].Turn >= turn) {
+		t.Fatalf("jumping to turn %d went to a frame at turn %d", turn, r.Frame().Turn)
+	}
+
+	at := r.Cursor()
+
+	if r.JumpToTurn(frames[last].Turn+1) || r.Cursor() != last {
+		t.Fatal("jumping past the last turn didn't stop at the end")
+	}
+
+	// A new replay simulates the steps it jumps over
+	fresh, err := match.NewReplay(m.Journal())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !fresh.JumpToTurn(turn) || fresh.Cursor() != at || !reflect.DeepEqual(fresh.Frame(), frames[at]) {
+		t.Fatal("jumping in a new replay went to another step")
+	}
+}
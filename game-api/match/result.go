@@ -0,0 +1,181 @@
+package match
+
+import (
+	"fmt"
+)
+
+// Drawn returns true if the match ended without a winner
+func (m *Match) Drawn() bool {
+	return m.ended && m.winner == nil
+}
+
+// DrawOffer returns the player whose draw offer is open, or nil
+func (m *Match) DrawOffer() *Player {
+	return m.drawOffer
+}
+
+// lose marks the player as beaten. The match ends once the effect that beat them is over,
+// so that players who are both beaten by the same effect draw
+func (p *Player) lose(reason string) {
+	if p.lost != "" {
+		return
+	}
+
+	p.lost = reason
+
+	p.match.checkLosses()
+}
+
+// checkLosses ends the match if a player was beaten, or draws it if both were
+func (m *Match) checkLosses() {
+	if m.ended || m.resolving > 0 || m.player1 == nil || m.player2 == nil {
+		return
+	}
+
+	switch {
+	case m.player1.lost != "" && m.player2.lost != "":
+		m.End(nil, fmt.Sprintf("%s and %s", m.player1.lost, m.player2.lost))
+	case m.player1.lost != "":
+		m.End(m.player2, m.player1.lost)
+	case m.player2.lost != "":
+		m.End(m.player1, m.player2.lost)
+	}
+}
+
+// beginInput marks an input as being resolved, it returns false if another one already is
+func (m *Match) beginInput() bool {
+	m.inputMutex.Lock()
+	defer m.inputMutex.Unlock()
+
+	if m.inputting {
+		return false
+	}
+
+	m.inputting = true
+
+	return true
+}
+
+// holdInputs waits for the input being resolved to be over and holds the match like an input until
+// endInput is called, other inputs are turned away meanwhile
+func (m *Match) holdInputs() {
+	m.inputMutex.Lock()
+	defer m.inputMutex.Unlock()
+
+	for m.inputting {
+		m.inputDone.Wait()
+	}
+
+	m.inputting = true
+}
+
+// endInput runs the settle inputs that arrived while the input was being resolved, in the order they
+// arrived, and then marks the input as over
+func (m *Match) endInput() {
+	for {
+		m.inputMutex.Lock()
+
+		if len(m.settling) < 1 {
+			m.inputting = false
+			m.ending = false
+			m.inputDone.Broadcast()
+			m.inputMutex.Unlock()
+			return
+		}
+
+		fn := m.settling[0]
+		m.settling = m.settling[1:]
+		m.inputMutex.Unlock()
+
+		fn()
+	}
+}
+
+// afterInput runs fn once the input being resolved is over, or at once if there is none. If fn may end
+// the match the open selections are answered with DefaultDecision, and so are the ones opened until
+// then, so that the input is not held up by the players
+func (m *Match) afterInput(fn func(), ends bool) {
+	m.inputMutex.Lock()
+
+	if m.inputting {
+		m.settling = append(m.settling, fn)
+		m.ending = m.ending || ends
+		m.inputMutex.Unlock()
+
+		if ends {
+			m.answerPrompts()
+		}
+
+		return
+	}
+
+	m.inputting = true
+	m.inputMutex.Unlock()
+
+	fn()
+	m.endInput()
+}
+
+// closing returns true once the match is over or about to be ended by a settle input
+func (m *Match) closing() bool {
+	m.inputMutex.Lock()
+	ending := m.ending
+	m.inputMutex.Unlock()
+
+	m.endMutex.Lock()
+	defer m.endMutex.Unlock()
+
+	return ending || m.ended
+}
+
+// answerPrompts answers the open selections of both players with DefaultDecision
+func (m *Match) answerPrompts() {
+	for _, p := range m.Players() {
+		if prompt, _ := p.openedPrompt(); prompt != nil {
+			p.answer(DefaultDecision(*prompt))
+		}
+	}
+}
+
+// settle handles the headers that end a match by the players' choice or their clock, they are accepted
+// at any time once the match has started, even while an input is being resolved
+func (m *Match) settle(p *Player, header string, data []byte) {
+	if !m.started || m.ended {
+		return
+	}
+
+	switch header {
+	case "concede":
+		{
+			m.record(p, header, data)
+			m.End(m.Opponent(p), fmt.Sprintf("%s conceded", p.name))
+		}
+	case "offer_draw":
+		{
+			if m.drawOffer != nil {
+				Warn(p, "A draw has already been offered")
+				return
+			}
+
+			m.record(p, header, data)
+			m.drawOffer = p
+
+			m.Chat("server", fmt.Sprintf("%s offers a draw", p.name))
+			m.BroadcastState()
+		}
+	case "accept_draw":
+		{
+			if m.drawOffer != m.Opponent(p) {
+				Warn(p, "Your opponent has not offered a draw")
+				return
+			}
+
+			m.record(p, header, data)
+			m.End(nil, "the players agreed to a draw")
+		}
+	case "timeout":
+		{
+			m.timeout(p, data)
+		}
+	}
+}
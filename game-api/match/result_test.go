@@ -0,0 +1,53 @@
+package match_test
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/jyotiskaghosh/ganjifa/game-api/match"
+)
+
+func TestConcedeWhileResolving(t *testing.T) {
+	m, _ := newMatch(t, 5)
+
+	conceded := false
+
+	for _, p := range m.Players() {
+		p.SetDecider(match.DeciderFunc(func(p *match.Player, prompt match.Prompt) match.Decision {
+			// The concession arrives while the input that raised the prompt is being resolved
+			if !conceded {
+				conceded = true
+				input(m, p, map[string]interface{}{"header": "concede"})
+
+				if m.Ended() {
+					t.Error("the match ended while an input was being resolved")
+				}
+			}
+
+			return match.DefaultDecision(prompt)
+		}))
+	}
+
+	rng := rand.New(rand.NewSource(5))
+	for i := 0; i < 200 && !conceded; i++ {
+		move(m, rng)
+	}
+
+	if !conceded {
+		t.Fatal("no player was prompted")
+	}
+
+	if !m.Ended() {
+		t.Fatal("the concession was dropped")
+	}
+
+	entries := m.Journal().Entries
+	if last := entries[len(entries)-1]; last.Header != "concede" {
+		t.Fatalf("the concession was journaled before the input was over, the last entry is %s", last.Header)
+	}
+
+	r, frames := replay(t, m.Journal())
+	if len(frames) < 1 || !r.Match().Ended() {
+		t.Fatal("the replay did not end")
+	}
+}
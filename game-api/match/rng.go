@@ -0,0 +1,59 @@
+package match
+
+import "math/rand"
+
+// idAlphabet is the set of characters used for card instance ids
+const idAlphabet = "0123456789abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ"
+
+// idLength is the length of generated card instance ids
+const idLength = 10
+
+// source is a splitmix64 random source. Its whole state is a single word,
+// which makes it easy to record, copy and restore
+type source struct {
+	state uint64
+}
+
+// newSource returns a source seeded with seed
+func newSource(seed int64) *source {
+	s := &source{}
+	s.Seed(seed)
+	return s
+}
+
+// Seed resets the source to the given seed
+func (s *source) Seed(seed int64) {
+	s.state = uint64(seed)
+}
+
+// Uint64 returns the next pseudo-random 64 bit value
+func (s *source) Uint64() uint64 {
+	s.state += 0x9e3779b97f4a7c15
+
+	z := s.state
+	z = (z ^ (z >> 30)) * 0xbf58476d1ce4e5b9
+	z = (z ^ (z >> 27)) * 0x94d049bb133111eb
+
+	return z ^ (z >> 31)
+}
+
+// Int63 returns the next pseudo-random non-negative 63 bit value
+func (s *source) Int63() int64 {
+	return int64(s.Uint64() >> 1)
+}
+
+// newRand returns a *rand.Rand backed by src
+func newRand(src *source) *rand.Rand {
+	return rand.New(src)
+}
+
+// newID returns a new card instance id drawn from the match's random source
+func (m *Match) newID() string {
+	b := make([]byte, idLength)
+
+	for i := range b {
+		b[i] = idAlphabet[m.rand.Intn(len(idAlphabet))]
+	}
+
+	return string(b)
+}
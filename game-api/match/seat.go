@@ -0,0 +1,114 @@
+package match
+
+import (
+	"errors"
+	"time"
+)
+
+// vacantSeat is the writer of a player who left the match, until they take their seat again
+type vacantSeat struct {
+	// player keeps the writers of both players apart, PlayerForWriter tells players apart by writer
+	player int
+}
+
+// Write drops the message
+func (w *vacantSeat) Write(msg interface{}) {}
+
+// Vacate frees the seat of a player whose connection was lost. The match is paused until they take
+// their seat again with Rebind
+func (m *Match) Vacate(p *Player) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if _, ok := p.writer.(*vacantSeat); ok {
+		return
+	}
+
+	if !m.Vacant() {
+		m.pause()
+	}
+
+	player := 1
+	if p == m.player2 {
+		player = 2
+	}
+
+	p.writer = &vacantSeat{player: player}
+}
+
+// Rebind seats the writer as the given player, whose seat must be vacant. The player is sent the
+// state of the match along with the selection they have to make, if any, and the match goes on
+// once every seat is taken
+func (m *Match) Rebind(p *Player, w Writer) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if _, ok := p.writer.(*vacantSeat); !ok {
+		return errors.New("this seat is taken")
+	}
+
+	p.writer = w
+
+	if !m.Vacant() {
+		m.resume()
+	}
+
+	m.BroadcastState()
+
+	if prompt, _ := p.openedPrompt(); prompt != nil {
+		m.writePrompt(p, *prompt)
+	}
+
+	return nil
+}
+
+// Vacant returns true while a player has left the match and not taken their seat again
+func (m *Match) Vacant() bool {
+	for _, p := range m.Players() {
+		if _, ok := p.writer.(*vacantSeat); ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Paused returns true while a match that has started waits for a player to take their seat again
+func (m *Match) Paused() bool {
+	return m.started && !m.ended && m.Vacant()
+}
+
+// pause stops the clocks
+func (m *Match) pause() {
+	m.runClock(nil)
+
+	m.clockMutex.Lock()
+	defer m.clockMutex.Unlock()
+
+	m.pausedAt = time.Now()
+}
+
+// resume restarts the clocks, the time spent paused is not counted against anybody
+func (m *Match) resume() {
+	m.clockMutex.Lock()
+	paused := time.Since(m.pausedAt)
+	m.turnStarted = m.turnStarted.Add(paused)
+	m.clockMutex.Unlock()
+
+	if !m.started {
+		return
+	}
+
+	actor := m.CurrentPlayer()
+
+	for _, p := range m.Players() {
+		p.mutex.Lock()
+		if p.prompt != nil {
+			p.promptOpened = p.promptOpened.Add(paused)
+			actor = p
+		}
+		p.mutex.Unlock()
+	}
+
+	m.runClock(actor)
+}
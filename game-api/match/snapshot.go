@@ -0,0 +1,314 @@
+package match
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// SnapshotVersion is the version of the snapshot format, snapshots of other versions can't be restored
+const SnapshotVersion = 2
+
+// Snapshot is the serializable state of a match between two inputs
+type Snapshot struct {
+	Version int `json:"version"`
+
+	Seed int64 `json:"seed"`
+	// RandState holds the bits of the random source's state, as an int64 to suit every encoding
+	RandState int64 `json:"rand_state"`
+
+	Started     bool        `json:"started"`
+	Phase       Phase       `json:"phase"`
+	TimeControl TimeControl `json:"time_control"`
+	Ended       bool        `json:"ended"`
+	// Winner is the index of the winner from 1, or 0 if there is none
+	Winner int `json:"winner"`
+	// DrawOffer is the index of the player whose draw offer is open from 1, or 0 if there is none
+	DrawOffer int `json:"draw_offer"`
+
+	Players []PlayerSnapshot `json:"players"`
+	Journal Journal          `json:"journal"`
+}
+
+// PlayerSnapshot is the state of a player in a Snapshot
+type PlayerSnapshot struct {
+	Name   string `json:"name"`
+	Life   int    `json:"life"`
+	Ready  bool   `json:"ready"`
+	Turn   bool   `json:"turn"`
+	TurnNo uint8  `json:"turn_no"`
+
+	// Clock is the thinking time the player had left
+	Clock time.Duration `json:"clock"`
+
+	Containers map[Container][]CardSnapshot `json:"containers"`
+}
+
+// CardSnapshot is the state of a card in a Snapshot
+type CardSnapshot struct {
+	ID         string              `json:"id"`
+	CardID     int                 `json:"card_id"`
+	Tapped     bool                `json:"tapped"`
+	AttachedTo string              `json:"attached_to"`
+	Conditions []ConditionSnapshot `json:"conditions"`
+	Counters   map[string]int      `json:"counters"`
+	// Token is the name of the token the card was created from, if it is one
+	Token string `json:"token,omitempty"`
+}
+
+// ConditionSnapshot is a condition of a card in a Snapshot
+type ConditionSnapshot struct {
+	Name     string   `json:"name"`
+	Duration Duration `json:"duration"`
+}
+
+// Snapshot returns the state of the match. The state of an input being resolved, such as while a player
+// is making a selection, can't be stored, the state from before the input is returned then
+func (m *Match) Snapshot() (Snapshot, error) {
+	for _, p := range m.Players() {
+		if p.busy() {
+			m.checkpointMutex.Lock()
+			defer m.checkpointMutex.Unlock()
+
+			if m.checkpoint == nil {
+				return Snapshot{}, errors.New("can't snapshot a match while an input is being resolved")
+			}
+
+			return *m.checkpoint, nil
+		}
+	}
+
+	return m.snapshot()
+}
+
+// saveCheckpoint keeps the state of a started match before an input is resolved
+func (m *Match) saveCheckpoint() {
+	if !m.started || m.headless {
+		return
+	}
+
+	s, err := m.snapshot()
+	if err != nil {
+		logrus.Debugf("Couldn't keep a checkpoint: %s", err)
+		return
+	}
+
+	m.checkpointMutex.Lock()
+	m.checkpoint = &s
+	m.checkpointMutex.Unlock()
+}
+
+// snapshot returns the state of a match that is between two inputs
+func (m *Match) snapshot() (Snapshot, error) {
+	s := Snapshot{
+		Version:     SnapshotVersion,
+		Seed:        m.seed,
+		RandState:   int64(m.src.state),
+		Started:     m.started,
+		Phase:       m.phase,
+		TimeControl: m.timeControl,
+		Ended:       m.ended,
+		Players:     make([]PlayerSnapshot, 0),
+		Journal:     m.Journal(),
+	}
+
+	for i, p := range m.Players() {
+		if m.winner == p {
+			s.Winner = i + 1
+		}
+
+		if m.drawOffer == p {
+			s.DrawOffer = i + 1
+		}
+
+		snapshot, err := p.snapshot()
+		if err != nil {
+			return Snapshot{}, err
+		}
+
+		snapshot.Clock = m.remainingClock(p)
+
+		s.Players = append(s.Players, snapshot)
+	}
+
+	return s, nil
+}
+
+// busy returns true while the player takes part in resolving an input
+func (p *Player) busy() bool {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	return p.wait || p.prompt != nil
+}
+
+// snapshot returns the state of the player
+func (p *Player) snapshot() (PlayerSnapshot, error) {
+	s := PlayerSnapshot{
+		Name:       p.name,
+		Life:       p.life,
+		Ready:      p.ready,
+		Turn:       p.turn,
+		TurnNo:     p.turnNo,
+		Containers: make(map[Container][]CardSnapshot),
+	}
+
+	for _, container := range AllContainers() {
+		cards := make([]CardSnapshot, 0)
+
+		for _, c := range p.CollectCards(container) {
+			card := CardSnapshot{
+				ID:         c.id,
+				CardID:     c.cardID,
+				Tapped:     c.Tapped,
+				Conditions: make([]ConditionSnapshot, 0),
+				Counters:   c.AllCounters(),
+			}
+
+			if c.attachedTo != nil {
+				card.AttachedTo = c.attachedTo.id
+			}
+
+			if c.token {
+				card.Token = c.name
+			}
+
+			for _, condition := range c.conditions {
+				name, err := conditionName(condition.handler)
+				if err != nil {
+					return PlayerSnapshot{}, fmt.Errorf("can't snapshot card %s(%s): %s", c.id, c.name, err)
+				}
+
+				card.Conditions = append(card.Conditions, ConditionSnapshot{Name: name, Duration: condition.duration})
+			}
+
+			cards = append(cards, card)
+		}
+
+		s.Containers[container] = cards
+	}
+
+	return s, nil
+}
+
+// Restore returns a working match from a snapshot. It is paused until its players have taken their
+// seats again with Rebind
+func Restore(s Snapshot) (*Match, error) {
+	if s.Version != SnapshotVersion {
+		return nil, fmt.Errorf("can't restore snapshot version %d, expected %d", s.Version, SnapshotVersion)
+	}
+
+	if len(s.Players) != 2 {
+		return nil, errors.New("snapshot must have exactly 2 players")
+	}
+
+	m := NewWithSeed(s.Seed)
+	m.src.state = uint64(s.RandState)
+	m.started = s.Started
+	m.phase = s.Phase
+	m.timeControl = s.TimeControl
+	m.ended = s.Ended
+	m.mulligan = s.Journal.Mulligan
+	m.journal = Journal{
+		Seed:     s.Journal.Seed,
+		Mulligan: s.Journal.Mulligan,
+		Players:  append(make([]string, 0), s.Journal.Players...),
+		Entries:  append(make([]JournalEntry, 0), s.Journal.Entries...),
+	}
+
+	cards := make(map[string]*Card)
+	attachments := make(map[*Card]string)
+
+	for i, snapshot := range s.Players {
+		p := newPlayer(snapshot.Name, &vacantSeat{player: i + 1}, m, snapshot.Turn)
+		p.life = snapshot.Life
+		p.ready = snapshot.Ready
+		p.turnNo = snapshot.TurnNo
+		p.clock = snapshot.Clock
+
+		for _, container := range AllContainers() {
+			ref, _ := p.containerRef(container)
+
+			for _, card := range snapshot.Containers[container] {
+				var c *Card
+				var err error
+
+				if card.Token != "" {
+					c, err = tokenCtor(card.Token)
+				} else {
+					c, err = CardCtor(card.CardID)
+				}
+
+				if err != nil {
+					return nil, err
+				}
+
+				c.id = card.ID
+				c.cardID = card.CardID
+				c.player = p
+				c.zone = container
+				c.Tapped = card.Tapped
+
+				c.counters = make(map[string]int)
+				for name, n := range card.Counters {
+					c.counters[name] = n
+				}
+
+				for _, cond := range card.Conditions {
+					handler, err := Condition(cond.Name)
+					if err != nil {
+						return nil, err
+					}
+
+					c.conditions = append(c.conditions, condition{handler: handler, duration: cond.Duration})
+				}
+
+				if card.AttachedTo != "" {
+					attachments[c] = card.AttachedTo
+				}
+
+				cards[c.id] = c
+				*ref = append(*ref, c)
+			}
+		}
+
+		if i == 0 {
+			m.player1 = p
+		} else {
+			m.player2 = p
+		}
+	}
+
+	for c, id := range attachments {
+		target, ok := cards[id]
+		if !ok {
+			return nil, fmt.Errorf("card %s is attached to missing card %s", c.id, id)
+		}
+
+		c.attachedTo = target
+	}
+
+	switch s.Winner {
+	case 1:
+		m.winner = m.player1
+	case 2:
+		m.winner = m.player2
+	}
+
+	switch s.DrawOffer {
+	case 1:
+		m.drawOffer = m.player1
+	case 2:
+		m.drawOffer = m.player2
+	}
+
+	// The match stays paused until both players are back, then the interrupted turn starts over
+	if m.started {
+		m.turnStarted = time.Now()
+		m.pausedAt = time.Now()
+	}
+
+	return m, nil
+}
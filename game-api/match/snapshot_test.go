@@ -0,0 +1,104 @@
+package match_test
+
+import (
+	"encoding/json"
+	"math/rand"
+	"reflect"
+	"testing"
+
+	"github.com/jyotiskaghosh/ganjifa/game-api/match"
+)
+
+// timeless drops what changes with the wall clock from a snapshot
+func timeless(s match.Snapshot) match.Snapshot {
+	players := make([]match.PlayerSnapshot, 0)
+
+	for _, p := range s.Players {
+		p.Clock = 0
+		players = append(players, p)
+	}
+
+	s.Players = players
+
+	return s
+}
+
+func TestSnapshotRoundTrip(t *testing.T) {
+	m, _ := newMatch(t, 7)
+	play(m, 7, 25)
+
+	if m.Ended() {
+		t.Fatal("the match ended before it could be snapshotted")
+	}
+
+	s, err := m.Snapshot()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := json.Marshal(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var decoded match.Snapshot
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+
+	restored, err := match.Restore(decoded)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	again, err := restored.Snapshot()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want, _ := json.Marshal(timeless(s))
+	got, _ := json.Marshal(timeless(again))
+
+	if string(want) != string(got) {
+		t.Errorf("restored snapshot differs\nwant %s\ngot  %s", want, got)
+	}
+}
+
+func TestSnapshotDuringPrompt(t *testing.T) {
+	m, _ := newMatch(t, 3)
+	rng := rand.New(rand.NewSource(3))
+
+	var during *match.Snapshot
+
+	for _, p := range m.Players() {
+		p.SetDecider(match.DeciderFunc(func(p *match.Player, prompt match.Prompt) match.Decision {
+			if during == nil {
+				s, err := m.Snapshot()
+				if err != nil {
+					t.Fatal(err)
+				}
+				during = &s
+			}
+
+			return match.DefaultDecision(prompt)
+		}))
+	}
+
+	for i := 0; i < 200 && !m.Ended(); i++ {
+		before, err := m.Snapshot()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		move(m, rng)
+
+		if during != nil {
+			if !reflect.DeepEqual(timeless(before), timeless(*during)) {
+				t.Error("the snapshot taken during a prompt is not the state from before the input")
+			}
+			return
+		}
+	}
+
+	t.Fatal("no prompt was opened")
+}
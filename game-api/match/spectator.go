@@ -0,0 +1,95 @@
+package match
+
+// AddSpectator lets the writer follow the match. Spectators get the state of the match with both
+// hands and trap zones hidden, the chat and highlights, but can't take part in it
+func (m *Match) AddSpectator(w Writer) {
+	m.spectatorsMutex.Lock()
+	m.spectators = append(m.spectators, w)
+	m.spectatorsMutex.Unlock()
+
+	if m.started {
+		w.Write(m.spectatorState(m.player1.denormalized(), m.player2.denormalized()))
+	}
+}
+
+// RemoveSpectator stops sending the match to the writer
+func (m *Match) RemoveSpectator(w Writer) {
+	m.spectatorsMutex.Lock()
+	defer m.spectatorsMutex.Unlock()
+
+	spectators := make([]Writer, 0)
+
+	for _, spectator := range m.spectators {
+		if spectator != w {
+			spectators = append(spectators, spectator)
+		}
+	}
+
+	m.spectators = spectators
+}
+
+// RemoveSpectators stops sending the match to every spectator and returns their writers
+func (m *Match) RemoveSpectators() []Writer {
+	m.spectatorsMutex.Lock()
+	defer m.spectatorsMutex.Unlock()
+
+	spectators := m.spectators
+	m.spectators = make([]Writer, 0)
+
+	return spectators
+}
+
+// IsSpectator returns true if the writer is spectating the match
+func (m *Match) IsSpectator(w Writer) bool {
+	m.spectatorsMutex.Lock()
+	defer m.spectatorsMutex.Unlock()
+
+	for _, spectator := range m.spectators {
+		if spectator == w {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Spectators returns the number of spectators
+func (m *Match) Spectators() int {
+	m.spectatorsMutex.Lock()
+	defer m.spectatorsMutex.Unlock()
+
+	return len(m.spectators)
+}
+
+// writeSpectators sends a message to every spectator
+func (m *Match) writeSpectators(msg interface{}) {
+	m.spectatorsMutex.Lock()
+	spectators := append(make([]Writer, 0), m.spectators...)
+	m.spectatorsMutex.Unlock()
+
+	for _, spectator := range spectators {
+		spectator.Write(msg)
+	}
+}
+
+// spectatorState returns the state of the match as spectators see it, from the side of the first player
+func (m *Match) spectatorState(player1 PlayerState, player2 PlayerState) StateMessage {
+	player1.Hand = hideCards(&player1.Hand)
+	player1.Trapzone = hideCards(&player1.Trapzone)
+
+	player2.Hand = hideCards(&player2.Hand)
+	player2.Trapzone = hideCards(&player2.Trapzone)
+
+	return StateMessage{
+		Header: "state_update",
+		State: State{
+			MyTurn:     m.player1.turn,
+			Phase:      m.phase,
+			Paused:     m.Paused(),
+			Stack:      m.stackState(),
+			Spectating: true,
+			Me:         player1,
+			Opponent:   player2,
+		},
+	}
+}
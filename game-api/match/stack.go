@@ -0,0 +1,171 @@
+package match
+
+import (
+	"fmt"
+)
+
+// StackItem is a spell, trap activation or triggered ability waiting to resolve
+type StackItem struct {
+	id      string
+	source  *Card
+	player  *Player
+	text    string
+	resolve func()
+
+	// tried holds the cards that were already played in response to the item, each may respond only once
+	tried map[*Card]bool
+}
+
+// ID ...
+func (item *StackItem) ID() string {
+	return item.id
+}
+
+// Source returns the card the item came from
+func (item *StackItem) Source() *Card {
+	return item.source
+}
+
+// Player returns the player who controls the item
+func (item *StackItem) Player() *Player {
+	return item.player
+}
+
+// Text describes the item
+func (item *StackItem) Text() string {
+	return item.text
+}
+
+// Push puts an item on top of the stack and resolves the stack down to where it was. Before each
+// item resolves both players get priority, starting with the opponent of its controller, and may
+// respond with a set down card, whose activation is pushed and resolved first
+func (m *Match) Push(source *Card, text string, resolve func()) {
+	m.stackCount++
+
+	base := len(m.stack)
+
+	m.stack = append(m.stack, &StackItem{
+		id:      fmt.Sprintf("stack%d", m.stackCount),
+		source:  source,
+		player:  source.player,
+		text:    text,
+		resolve: resolve,
+		tried:   make(map[*Card]bool),
+	})
+
+	m.BroadcastState()
+
+	for len(m.stack) > base {
+		if m.ended {
+			m.stack = m.stack[:base]
+			return
+		}
+
+		top := m.stack[len(m.stack)-1]
+
+		if m.respond(top) {
+			continue
+		}
+
+		m.stack = m.stack[:len(m.stack)-1]
+
+		top.resolve()
+		m.BroadcastState()
+	}
+}
+
+// Stack returns the items waiting to resolve, the last one resolves first
+func (m *Match) Stack() []*StackItem {
+	return append(make([]*StackItem, 0), m.stack...)
+}
+
+// ActivateTrap puts the activation of a set down card on the stack.
+// attacker is the creature the card answers, or nil if it is played in response to the stack
+func (m *Match) ActivateTrap(c *Card, attacker *Card) {
+	m.Push(c, fmt.Sprintf("%s activated %s", c.player.name, c.name), func() {
+		// The card may have been removed in response
+		if c.zone != TRAPZONE {
+			m.Chat("server", fmt.Sprintf("%s fizzled", c.name))
+			return
+		}
+
+		m.HandleFx(NewContext(m, &TrapEvent{
+			ID:       c.id,
+			Attacker: attacker,
+		}))
+	})
+}
+
+// CanActivate returns true if the set down card has an effect when activated against the attacker,
+// or in response to the stack if attacker is nil
+func (m *Match) CanActivate(c *Card, attacker *Card) bool {
+	if c.zone != TRAPZONE || c.Tapped {
+		return false
+	}
+
+	event := &TrapEvent{ID: c.id, Attacker: attacker}
+
+	return m.DryRun(event) && event.Activated
+}
+
+// respond gives both players priority over the top item of the stack and returns true if one of them responded.
+// A player is only asked if they have a set down card that can respond
+func (m *Match) respond(item *StackItem) bool {
+	for _, p := range []*Player{m.Opponent(item.player), item.player} {
+		cards := Filter(p.CollectCards(TRAPZONE), func(c *Card) bool {
+			return !item.tried[c] && !m.onStack(c) && m.CanActivate(c, nil)
+		})
+
+		if len(cards) < 1 {
+			continue
+		}
+
+		selected, cancelled := p.Prompt(Prompt{
+			Cards:       cards,
+			Text:        fmt.Sprintf("%s, you may respond with a set down card", item.text),
+			Min:         1,
+			Max:         1,
+			Cancellable: true,
+			Source:      item.source,
+			Response:    true,
+		})
+
+		if cancelled || len(selected) < 1 {
+			continue
+		}
+
+		item.tried[selected[0]] = true
+		m.ActivateTrap(selected[0], nil)
+
+		return true
+	}
+
+	return false
+}
+
+// onStack returns true if the card is the source of an item on the stack
+func (m *Match) onStack(c *Card) bool {
+	for _, item := range m.stack {
+		if item.source == c {
+			return true
+		}
+	}
+
+	return false
+}
+
+// stackState returns the items on the stack as they are sent to clients, the last one resolves first
+func (m *Match) stackState() []StackItemState {
+	result := make([]StackItemState, 0)
+
+	for _, item := range m.stack {
+		result = append(result, StackItemState{
+			ID:     item.id,
+			Card:   item.source.denormalizeCard(),
+			Player: item.player.name,
+			Text:   item.text,
+		})
+	}
+
+	return result
+}
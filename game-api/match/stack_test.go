@@ -0,0 +1,201 @@
+package match_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/jyotiskaghosh/ganjifa/game-api/civ"
+	"github.com/jyotiskaghosh/ganjifa/game-api/family"
+	"github.com/jyotiskaghosh/ganjifa/game-api/fx"
+	"github.com/jyotiskaghosh/ganjifa/game-api/match"
+)
+
+// Cards made up for the stack tests
+const (
+	caster = 1010 + iota
+	bolt
+	recall
+	lurker
+)
+
+// spell returns the effects of a spell that targets 1 creature of whose, and does effect to it
+func spell(whose func(card *match.Card, ctx *match.Context) *match.Player, effect func(target *match.Card)) match.HandlerFunc {
+	return match.Subscribe(func(card *match.Card, ctx *match.Context) {
+		switch event := ctx.Event().(type) {
+		case *match.PlayCardEvent:
+			if event.ID == card.ID() {
+				card.SpellCast(ctx, func() []*match.Card {
+					return card.Player().Search(whose(card, ctx).CollectCards(match.BATTLEZONE), "Select 1 creature", 1, 1, false)
+				})
+			}
+		case *match.SpellCast:
+			if event.ID == card.ID() {
+				for _, c := range event.Targets {
+					effect(c)
+				}
+			}
+		default:
+			fx.Spell(card, ctx)
+		}
+	}, &match.PlayCardEvent{}, &match.SpellCast{}, &match.TrapEvent{})
+}
+
+func init() {
+	opponent := func(card *match.Card, ctx *match.Context) *match.Player { return ctx.Match().Opponent(card.Player()) }
+	own := func(card *match.Card, ctx *match.Context) *match.Player { return card.Player() }
+
+	builders := map[int]match.CardBuilder{
+		caster: {
+			Name:    "Caster",
+			Rank:    3,
+			Civ:     civ.AGNI,
+			Family:  family.Human,
+			Attack:  1,
+			Defence: 1,
+			Effects: []match.HandlerFunc{fx.Creature},
+		},
+		bolt: {
+			Name:   "Bolt",
+			Civ:    civ.AGNI,
+			Family: family.Spell,
+			Effects: []match.HandlerFunc{spell(opponent, func(target *match.Card) {
+				if err := target.MoveCard(match.GRAVEYARD); err != nil {
+					panic(err)
+				}
+			})},
+		},
+		recall: {
+			Name:   "Recall",
+			Civ:    civ.AGNI,
+			Family: family.Spell,
+			Effects: []match.HandlerFunc{spell(own, func(target *match.Card) {
+				if err := target.MoveCard(match.HAND); err != nil {
+					panic(err)
+				}
+			})},
+		},
+		lurker: {
+			Name:    "Lurker",
+			Civ:     civ.AGNI,
+			Family:  family.Beast,
+			Attack:  1,
+			Defence: 1,
+			Effects: []match.HandlerFunc{fx.Creature, fx.Ambush},
+		},
+	}
+
+	for uid, cb := range builders {
+		cb := cb
+		match.AddCard(uid, cb.Build)
+	}
+}
+
+func TestResponseOrder(t *testing.T) {
+	m, _ := newMatchWith(t, 1, testDeck(caster, recall))
+	a := m.CurrentPlayer()
+
+	asked := make([]string, 0)
+
+	for _, p := range m.Players() {
+		put(t, take(t, p, caster), match.BATTLEZONE)
+		put(t, take(t, p, recall), match.TRAPZONE)
+
+		p.SetDecider(match.DeciderFunc(func(p *match.Player, prompt match.Prompt) match.Decision {
+			if prompt.Response {
+				asked = append(asked, p.Name())
+			}
+
+			return match.DefaultDecision(prompt)
+		}))
+	}
+
+	resolved := false
+	m.Push(a.CollectCards(match.BATTLEZONE)[0], "Test", func() { resolved = true })
+
+	if !resolved {
+		t.Fatal("the item didn't resolve")
+	}
+
+	if want := []string{m.Opponent(a).Name(), a.Name()}; !reflect.DeepEqual(asked, want) {
+		t.Fatalf("the players were asked to respond in the order %v instead of %v", asked, want)
+	}
+}
+
+func TestSpellFizzles(t *testing.T) {
+	m, _ := newMatchWith(t, 1, testDeck(caster, bolt, recall))
+	a := m.CurrentPlayer()
+	b := m.Opponent(a)
+
+	put(t, take(t, a, caster), match.BATTLEZONE)
+
+	cast := take(t, a, bolt)
+	put(t, cast, match.HAND)
+
+	target := take(t, b, caster)
+	put(t, target, match.BATTLEZONE)
+
+	trap := take(t, b, recall)
+	put(t, trap, match.TRAPZONE)
+
+	// The opponent saves the target by returning it to their hand
+	b.SetDecider(match.DeciderFunc(func(p *match.Player, prompt match.Prompt) match.Decision {
+		if prompt.Response {
+			return match.Decision{Cards: []string{trap.ID()}}
+		}
+
+		return match.DefaultDecision(prompt)
+	}))
+
+	input(m, a, map[string]interface{}{"header": "play_card", "id": cast.ID()})
+
+	if target.Zone() != match.HAND {
+		t.Fatalf("the target is in %s instead of the hand it was returned to", target.Zone())
+	}
+
+	if cast.Zone() != match.GRAVEYARD || trap.Zone() != match.GRAVEYARD {
+		t.Fatalf("the spells are in %s and %s instead of the graveyard", cast.Zone(), trap.Zone())
+	}
+}
+
+func TestCanActivate(t *testing.T) {
+	m, _ := newMatchWith(t, 1, testDeck(caster, recall, lurker))
+	a := m.CurrentPlayer()
+
+	attacker := take(t, m.Opponent(a), caster)
+	put(t, attacker, match.BATTLEZONE)
+
+	ambush := take(t, a, lurker)
+	put(t, ambush, match.TRAPZONE)
+
+	trap := take(t, a, recall)
+	put(t, trap, match.TRAPZONE)
+
+	if m.CanActivate(trap, nil) {
+		t.Fatal("a spell could be activated without a creature to cast it")
+	}
+
+	if m.CanActivate(ambush, nil) {
+		t.Fatal("an ambush could be activated in response to the stack")
+	}
+
+	if !m.CanActivate(ambush, attacker) {
+		t.Fatal("an ambush couldn't be activated against an attacker")
+	}
+
+	put(t, take(t, a, caster), match.BATTLEZONE)
+
+	if !m.CanActivate(trap, nil) {
+		t.Fatal("a spell couldn't be activated with a creature to cast it")
+	}
+
+	trap.SetTapped(true)
+
+	if m.CanActivate(trap, nil) {
+		t.Fatal("a tapped card could be activated")
+	}
+
+	// Asking is a dry run, nothing is played
+	if ambush.Zone() != match.TRAPZONE || trap.Zone() != match.TRAPZONE || len(m.Stack()) > 0 {
+		t.Fatal("checking the cards played them")
+	}
+}
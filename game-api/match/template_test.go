@@ -0,0 +1,77 @@
+package match_test
+
+import (
+	"testing"
+
+	"github.com/jyotiskaghosh/ganjifa/game-api/civ"
+	"github.com/jyotiskaghosh/ganjifa/game-api/family"
+	"github.com/jyotiskaghosh/ganjifa/game-api/fx"
+	"github.com/jyotiskaghosh/ganjifa/game-api/match"
+)
+
+// Cards made up for the tests, out of the way of the sets
+const (
+	gaja = 1000 + iota
+	villager
+)
+
+// beastly makes the card a beast as well as its own family
+var beastly = match.Subscribe(func(card *match.Card, ctx *match.Context) {
+	if event, ok := ctx.Event().(*match.ContinuousEvent); ok && event.Layer == match.CharacteristicLayer && event.ID == card.ID() {
+		event.Stats.Family[family.Beast] = true
+	}
+}, &match.ContinuousEvent{})
+
+func init() {
+	bonus, err := fx.Effect("FamilyBonus", fx.Params{"family": family.Beast, "amount": float64(1)})
+	if err != nil {
+		panic(err)
+	}
+
+	builders := map[int]match.CardBuilder{
+		gaja: {
+			Name:    "Gaja",
+			Civ:     civ.PRITHVI,
+			Family:  family.Beast,
+			Attack:  2,
+			Defence: 2,
+			Effects: []match.HandlerFunc{fx.Creature, bonus},
+		},
+		villager: {
+			Name:    "Villager",
+			Civ:     civ.PRITHVI,
+			Family:  family.Human,
+			Attack:  1,
+			Defence: 1,
+			Effects: []match.HandlerFunc{fx.Creature, beastly},
+		},
+	}
+
+	for uid, cb := range builders {
+		cb := cb
+		match.AddCard(uid, cb.Build)
+	}
+}
+
+func TestFamilyBonus(t *testing.T) {
+	m, _ := newMatchWith(t, 1, testDeck(gaja, villager))
+	p := m.Players()[0]
+
+	played := take(t, p, gaja)
+	put(t, played, match.BATTLEZONE)
+	put(t, take(t, p, villager), match.BATTLEZONE)
+
+	held := take(t, p, gaja)
+	put(t, held, match.HAND)
+
+	ctx := match.NewContext(m, &match.EndTurnEvent{})
+
+	// The villager only counts as a beast through its effect
+	if attack, defence := played.GetAttack(ctx), played.GetDefence(ctx); attack != 3 || defence != 3 {
+		t.Fatalf("the card in play has %d/%d instead of 3/3", attack, defence)
+	}
+
+	if attack, defence := held.GetAttack(ctx), held.GetDefence(ctx); attack != 2 || defence != 2 {
+		t.Fatalf("the card in hand has %d/%d instead of 2/2", attack, defence)
+	}
+}
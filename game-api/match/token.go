@@ -0,0 +1,79 @@
+package match
+
+import (
+	"fmt"
+
+	"github.com/sirupsen/logrus"
+)
+
+// TokenID is the card id of tokens, they don't belong to any set
+const TokenID = -1
+
+var tokens = make(map[string]CardBuilder)
+
+// RegisterToken makes a token known by its name, so that cards created from it can be stored
+// in a snapshot. It should be called from init functions
+func RegisterToken(cb CardBuilder) {
+	tokens[cb.Name] = cb
+}
+
+// tokenCtor returns a token built from the builder registered with the given name
+func tokenCtor(name string) (*Card, error) {
+	cb, ok := tokens[name]
+	if !ok {
+		return nil, fmt.Errorf("token %s is not registered", name)
+	}
+
+	c := cb.Build()
+	c.token = true
+
+	return c, nil
+}
+
+// CreateToken builds the token registered with the given name straight into the player's battlezone.
+// Tokens don't come from a deck, and vanish when they would leave play
+func (p *Player) CreateToken(name string) (*Card, error) {
+	c, err := tokenCtor(name)
+	if err != nil {
+		return nil, err
+	}
+
+	c.id = p.match.newID()
+	c.cardID = TokenID
+	c.player = p
+	c.zone = BATTLEZONE
+
+	ref, _ := p.containerRef(BATTLEZONE)
+	*ref = append(*ref, c)
+
+	p.match.HandleFx(NewContext(p.match, &TokenCreated{ID: c.id}))
+
+	return c, nil
+}
+
+// Token returns true if the card is a token
+func (c *Card) Token() bool {
+	return c.token
+}
+
+// vanish takes a token that left play out of the match, anything attached to it goes where it would have gone
+func (c *Card) vanish(destination Container) {
+	f := c.zone
+	c.zone = ""
+
+	if f == SOUL {
+		c.Detach()
+	}
+
+	c.player.match.HandleFx(NewContext(c.player.match, &TokenVanished{
+		ID:   c.id,
+		From: f,
+		To:   destination,
+	}))
+
+	for _, card := range c.Attachments() {
+		if err := card.MoveCard(destination); err != nil {
+			logrus.Debugf("vanish: %s", err)
+		}
+	}
+}
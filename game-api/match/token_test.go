@@ -0,0 +1,131 @@
+package match_test
+
+import (
+	"testing"
+
+	"github.com/jyotiskaghosh/ganjifa/game-api/civ"
+	"github.com/jyotiskaghosh/ganjifa/game-api/family"
+	"github.com/jyotiskaghosh/ganjifa/game-api/fx"
+	"github.com/jyotiskaghosh/ganjifa/game-api/match"
+)
+
+func init() {
+	match.RegisterToken(match.CardBuilder{
+		Name:    "Sprite",
+		Civ:     civ.AGNI,
+		Family:  family.Insect,
+		Attack:  1,
+		Defence: 1,
+		Effects: []match.HandlerFunc{fx.Creature},
+	})
+}
+
+// sprite creates a sprite token for the player
+func sprite(t *testing.T, p *match.Player) *match.Card {
+	c, err := p.CreateToken("Sprite")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return c
+}
+
+// everywhere lists every container a card can be in
+var everywhere = []match.Container{match.DECK, match.HAND, match.TRAPZONE, match.GRAVEYARD, match.BATTLEZONE, match.SOUL}
+
+// battlezoneState returns the state of the card in the battlezone that was last sent to the player
+func battlezoneState(t *testing.T, w *recorder, id string) match.CardState {
+	if len(w.states) < 1 {
+		t.Fatal("no state was sent")
+	}
+
+	for _, c := range w.states[len(w.states)-1].State.Me.Battlezone {
+		if c.ID == id {
+			return c
+		}
+	}
+
+	t.Fatalf("%s is not in the battlezone that was sent", id)
+	return match.CardState{}
+}
+
+func TestCreateToken(t *testing.T) {
+	m, writers := newMatch(t, 1)
+	p := m.Players()[0]
+
+	c := sprite(t, p)
+
+	if c.Zone() != match.BATTLEZONE || !p.HasCard(c.ID(), match.BATTLEZONE) {
+		t.Fatalf("the token is in %s instead of the battlezone", c.Zone())
+	}
+
+	if !c.Token() || c.CardID() != match.TokenID {
+		t.Fatal("the card isn't a token")
+	}
+
+	if !battlezoneState(t, writers[0], c.ID()).Token {
+		t.Fatal("the token was sent as a card")
+	}
+
+	if _, err := p.CreateToken("Unknown"); err == nil {
+		t.Fatal("a token that isn't registered was created")
+	}
+}
+
+func TestTokenVanishes(t *testing.T) {
+	m, _ := newMatchWith(t, 1, testDeck(caster))
+	p := m.Players()[0]
+
+	c := sprite(t, p)
+
+	attached := take(t, p, caster)
+	attached.AttachTo(c)
+
+	if attached.Zone() != match.SOUL || attached.AttachedTo() != c {
+		t.Fatal("the card wasn't attached to the token")
+	}
+
+	put(t, c, match.GRAVEYARD)
+
+	if p.HasCard(c.ID(), everywhere...) || c.Zone() != "" {
+		t.Fatalf("the token went to %s instead of vanishing", c.Zone())
+	}
+
+	if attached.Zone() != match.GRAVEYARD || attached.AttachedTo() != nil {
+		t.Fatalf("the card attached to the token is in %s instead of the graveyard", attached.Zone())
+	}
+}
+
+func TestTokenUnderEvolution(t *testing.T) {
+	m, writers := newMatchWith(t, 1, testDeck(caster))
+	p := m.Players()[0]
+
+	c := sprite(t, p)
+
+	evolved := take(t, p, caster)
+	put(t, evolved, match.HAND)
+
+	c.EvolveTo(evolved)
+
+	if evolved.Zone() != match.BATTLEZONE || c.Zone() != match.SOUL || c.AttachedTo() != evolved {
+		t.Fatal("the token isn't the soul of the evolved creature")
+	}
+
+	// Evolving is part of resolving an effect, whose state is sent once it is over
+	m.BroadcastState()
+
+	soul := battlezoneState(t, writers[0], evolved.ID()).AttachedCards
+	if len(soul) != 1 || !soul[0].Token {
+		t.Fatal("the soul wasn't sent as a token")
+	}
+
+	put(t, evolved, match.GRAVEYARD)
+
+	if evolved.Zone() != match.GRAVEYARD {
+		t.Fatalf("the evolved creature is in %s instead of the graveyard", evolved.Zone())
+	}
+
+	if p.HasCard(c.ID(), everywhere...) || c.Zone() != "" {
+		t.Fatalf("the token under the evolution went to %s instead of vanishing", c.Zone())
+	}
+}
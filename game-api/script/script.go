@@ -0,0 +1,238 @@
+// Package script runs card effects written in Starlark, a small dialect of python, so that cards can be
+// designed without writing Go. A script defines functions named after the hooks it wants, such as
+//
+//	def on_enter(card):
+//		def search():
+//			deck = [c for c in card.owner.cards("deck") if c.family == "beast"]
+//			for c in card.owner.search(deck, "Select 1 beast", 0, 1, True):
+//				c.move("hand")
+//			card.owner.shuffle()
+//
+//		push(card.name + " searches for a beast", search)
+//
+// Scripts can't load other files or reach anything outside the match, and each call into a script may
+// take at most MaxSteps steps. Variables of a script are frozen once it is loaded, a card keeps its
+// state in counters and conditions instead
+package script
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+
+	"github.com/sirupsen/logrus"
+	"go.starlark.net/starlark"
+
+	"github.com/jyotiskaghosh/ganjifa/game-api/fx"
+	"github.com/jyotiskaghosh/ganjifa/game-api/match"
+)
+
+// MaxSteps is the number of steps a script may take each time it is called, before it is stopped
+var MaxSteps uint64 = 100000
+
+// triggers are the hooks called by the fx triggers of the same name, with the card and
+// whatever the trigger adds
+var triggers = []struct {
+	hook string
+	on   func(match.HandlerFunc) match.HandlerFunc
+	args func(ctx *match.Context) starlark.Tuple
+}{
+	{hook: "on_enter", on: fx.OnEnterBattlezone},
+	{hook: "on_leave", on: fx.OnLeaveBattlezone},
+	{hook: "on_destroyed", on: fx.OnDestroyed},
+	{hook: "on_attack", on: fx.OnAttack},
+	{hook: "on_block", on: fx.OnBlock},
+	{hook: "at_start_of_turn", on: fx.AtStartOfYourTurn},
+	{hook: "at_end_of_turn", on: fx.AtEndOfYourTurn},
+	{hook: "on_damage_dealt", on: fx.OnDamageDealt, args: func(ctx *match.Context) starlark.Tuple {
+		if event, ok := ctx.Event().(*match.DamageEvent); ok {
+			return starlark.Tuple{starlark.MakeInt(int(event.Health))}
+		}
+		return starlark.Tuple{starlark.MakeInt(0)}
+	}},
+}
+
+// hooks are the functions a script may define, the engine calls them as follows
+var hooks = map[string]string{
+	"on_enter":         "on_enter(card), when the card enters its battlezone",
+	"on_leave":         "on_leave(card), when the card leaves its battlezone",
+	"on_destroyed":     "on_destroyed(card), when the card is destroyed",
+	"on_attack":        "on_attack(card), once the card attacked",
+	"on_block":         "on_block(card), once the card blocked",
+	"at_start_of_turn": "at_start_of_turn(card), at the start of each turn of the card's owner",
+	"at_end_of_turn":   "at_end_of_turn(card), at the end of each turn of the card's owner",
+	"on_damage_dealt":  "on_damage_dealt(card, amount), when the card deals damage to a player",
+	"can_play":         "can_play(card), the card can't be played unless it returns True",
+	"targets":          "targets(card), returns the targets of a spell once it is played",
+	"on_cast":          "on_cast(card, targets), when the spell resolves",
+	"attack_bonus":     "attack_bonus(card), returns what is added to the attack of the card",
+	"defence_bonus":    "defence_bonus(card), returns what is added to the defence of the card",
+}
+
+// Script is a compiled script
+type Script struct {
+	name    string
+	globals starlark.StringDict
+}
+
+// Compile runs the top level of the script once, name is used in error messages
+func Compile(name string, src []byte) (*Script, error) {
+	globals, err := starlark.ExecFile(newThread(name, nil, nil), name, src, builtins)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %s", name, err)
+	}
+
+	for hook := range hooks {
+		if v, ok := globals[hook]; ok {
+			if _, ok := v.(starlark.Callable); !ok {
+				return nil, fmt.Errorf("%s: %s must be a function, such as %s", name, hook, hooks[hook])
+			}
+		}
+	}
+
+	return &Script{name: name, globals: globals}, nil
+}
+
+// Load compiles the script in the file
+func Load(path string) (*Script, error) {
+	src, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return Compile(filepath.Base(path), src)
+}
+
+// Targets returns true if the script chooses the targets of a spell, its handlers then play the spell
+// in place of fx.Spell
+func (s *Script) Targets() bool {
+	return s.function("targets") != nil
+}
+
+// Handlers returns a handler for each hook of the script
+func (s *Script) Handlers() []match.HandlerFunc {
+	handlers := make([]match.HandlerFunc, 0)
+
+	for _, t := range triggers {
+		fn := s.function(t.hook)
+		if fn == nil {
+			continue
+		}
+
+		args := t.args
+		handlers = append(handlers, t.on(func(card *match.Card, ctx *match.Context) {
+			extra := starlark.Tuple{}
+			if args != nil {
+				extra = args(ctx)
+			}
+
+			s.call(fn, card, ctx, append(starlark.Tuple{newCard(card, ctx)}, extra...))
+		}))
+	}
+
+	if fn := s.function("can_play"); fn != nil {
+		handlers = append(handlers, match.Subscribe(func(card *match.Card, ctx *match.Context) {
+			if event, ok := ctx.Event().(*match.PlayCardEvent); ok && event.ID == card.ID() {
+				if v := s.call(fn, card, ctx, starlark.Tuple{newCard(card, ctx)}); v == nil || !v.Truth() {
+					ctx.InterruptFlow()
+				}
+			}
+		}, &match.PlayCardEvent{}))
+	}
+
+	if fn := s.function("targets"); fn != nil {
+		handlers = append(handlers, match.Subscribe(func(card *match.Card, ctx *match.Context) {
+			if event, ok := ctx.Event().(*match.PlayCardEvent); ok && event.ID == card.ID() {
+				card.SpellCast(ctx, func() []*match.Card {
+					targets, err := toCards(s.call(fn, card, ctx, starlark.Tuple{newCard(card, ctx)}))
+					if err != nil {
+						logrus.Debugf("%s: targets: %s", s.name, err)
+					}
+
+					return targets
+				})
+				return
+			}
+
+			fx.Spell(card, ctx)
+		}, &match.PlayCardEvent{}, &match.TrapEvent{}))
+	}
+
+	if fn := s.function("on_cast"); fn != nil {
+		handlers = append(handlers, match.Subscribe(func(card *match.Card, ctx *match.Context) {
+			if event, ok := ctx.Event().(*match.SpellCast); ok && event.ID == card.ID() {
+				ctx.ScheduleAfter(func() {
+					s.call(fn, card, ctx, starlark.Tuple{newCard(card, ctx), newCards(event.Targets, ctx)})
+				})
+			}
+		}, &match.SpellCast{}))
+	}
+
+	if fn := s.function("attack_bonus"); fn != nil {
+		handlers = append(handlers, match.Subscribe(func(card *match.Card, ctx *match.Context) {
+			if event, ok := ctx.Event().(*match.ContinuousEvent); ok && event.Layer == match.ModifierLayer && event.ID == card.ID() {
+				fx.AttackModifier(card, ctx, s.bonus(fn, card, ctx))
+			}
+		}, &match.ContinuousEvent{}))
+	}
+
+	if fn := s.function("defence_bonus"); fn != nil {
+		handlers = append(handlers, match.Subscribe(func(card *match.Card, ctx *match.Context) {
+			if event, ok := ctx.Event().(*match.ContinuousEvent); ok && event.Layer == match.ModifierLayer && event.ID == card.ID() {
+				fx.DefenceModifier(card, ctx, s.bonus(fn, card, ctx))
+			}
+		}, &match.ContinuousEvent{}))
+	}
+
+	return handlers
+}
+
+// function returns the hook with the given name, or nil if the script doesn't define it
+func (s *Script) function(hook string) starlark.Callable {
+	fn, _ := s.globals[hook].(starlark.Callable)
+	return fn
+}
+
+// call runs fn for the card in a new thread. If the script fails or runs out of steps the error is logged
+// and nil is returned
+func (s *Script) call(fn starlark.Callable, card *match.Card, ctx *match.Context, args starlark.Tuple) starlark.Value {
+	v, err := starlark.Call(newThread(s.name, card, ctx), fn, args, nil)
+	if err != nil {
+		logrus.Debugf("%s: %s", s.name, err)
+		return nil
+	}
+
+	return v
+}
+
+// bonus returns what a bonus hook adds to a stat of the card
+func (s *Script) bonus(fn starlark.Callable, card *match.Card, ctx *match.Context) uint8 {
+	v := s.call(fn, card, ctx, starlark.Tuple{newCard(card, ctx)})
+	if v == nil {
+		return 0
+	}
+
+	var n int
+	if err := starlark.AsInt(v, &n); err != nil || n < 0 || n > 255 {
+		logrus.Debugf("%s: %s must return a whole number from 0 to 255, got %s", s.name, fn.Name(), v)
+		return 0
+	}
+
+	return uint8(n)
+}
+
+// newThread returns a thread limited to MaxSteps that runs the script for the card
+func newThread(name string, card *match.Card, ctx *match.Context) *starlark.Thread {
+	thread := &starlark.Thread{
+		Name: name,
+		Print: func(_ *starlark.Thread, msg string) {
+			logrus.Debugf("%s: %s", name, msg)
+		},
+	}
+
+	thread.SetMaxExecutionSteps(MaxSteps)
+	thread.SetLocal("card", card)
+	thread.SetLocal("ctx", ctx)
+
+	return thread
+}
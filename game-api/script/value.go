@@ -0,0 +1,618 @@
+package script
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/sirupsen/logrus"
+	"go.starlark.net/starlark"
+	"go.starlark.net/syntax"
+
+	"github.com/jyotiskaghosh/ganjifa/game-api/civ"
+	"github.com/jyotiskaghosh/ganjifa/game-api/match"
+)
+
+// builtins are the functions every script can call
+var builtins = starlark.StringDict{
+	"chat": starlark.NewBuiltin("chat", chat),
+	"push": starlark.NewBuiltin("push", push),
+}
+
+// caller returns the card and the context the script was called for
+func caller(thread *starlark.Thread, name string) (*match.Card, *match.Context, error) {
+	card, _ := thread.Local("card").(*match.Card)
+	ctx, _ := thread.Local("ctx").(*match.Context)
+
+	if card == nil || ctx == nil {
+		return nil, nil, fmt.Errorf("%s can only be called from a hook", name)
+	}
+
+	return card, ctx, nil
+}
+
+// chat(message) sends a message to both players
+func chat(thread *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var message string
+	if err := starlark.UnpackArgs(b.Name(), args, kwargs, "message", &message); err != nil {
+		return nil, err
+	}
+
+	_, ctx, err := caller(thread, b.Name())
+	if err != nil {
+		return nil, err
+	}
+
+	ctx.Match().Chat("Server", message)
+
+	return starlark.None, nil
+}
+
+// push(text, fn) puts fn on the stack, it is called once both players had a chance to respond
+func push(thread *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var text string
+	var fn starlark.Callable
+	if err := starlark.UnpackArgs(b.Name(), args, kwargs, "text", &text, "fn", &fn); err != nil {
+		return nil, err
+	}
+
+	card, ctx, err := caller(thread, b.Name())
+	if err != nil {
+		return nil, err
+	}
+
+	name := thread.Name
+	ctx.Match().Push(card, text, func() {
+		if _, err := starlark.Call(newThread(name, card, ctx), fn, nil, nil); err != nil {
+			logrus.Debugf("%s: %s", name, err)
+		}
+	})
+
+	return starlark.None, nil
+}
+
+// card is a match.Card as seen by scripts
+type card struct {
+	card *match.Card
+	ctx  *match.Context
+}
+
+func newCard(c *match.Card, ctx *match.Context) starlark.Value {
+	if c == nil {
+		return starlark.None
+	}
+
+	return &card{card: c, ctx: ctx}
+}
+
+func newCards(cards []*match.Card, ctx *match.Context) *starlark.List {
+	values := make([]starlark.Value, 0)
+
+	for _, c := range cards {
+		values = append(values, newCard(c, ctx))
+	}
+
+	return starlark.NewList(values)
+}
+
+// toCards returns the cards of a list, or any other iterable, of cards
+func toCards(v starlark.Value) ([]*match.Card, error) {
+	result := make([]*match.Card, 0)
+
+	if v == nil || v == starlark.None {
+		return result, nil
+	}
+
+	iterable, ok := v.(starlark.Iterable)
+	if !ok {
+		return result, fmt.Errorf("want a list of cards, got %s", v.Type())
+	}
+
+	iter := iterable.Iterate()
+	defer iter.Done()
+
+	var x starlark.Value
+	for iter.Next(&x) {
+		c, ok := x.(*card)
+		if !ok {
+			return make([]*match.Card, 0), fmt.Errorf("want a list of cards, got a %s in it", x.Type())
+		}
+
+		result = append(result, c.card)
+	}
+
+	return result, nil
+}
+
+func (c *card) String() string        { return fmt.Sprintf("<card %s %s>", c.card.ID(), c.card.Name()) }
+func (c *card) Type() string          { return "card" }
+func (c *card) Freeze()               {}
+func (c *card) Truth() starlark.Bool  { return starlark.True }
+func (c *card) Hash() (uint32, error) { return starlark.String(c.card.ID()).Hash() }
+
+func (c *card) CompareSameType(op syntax.Token, y starlark.Value, depth int) (bool, error) {
+	return compare(op, c.card == y.(*card).card)
+}
+
+func (c *card) AttrNames() []string {
+	return append([]string{
+		"attached_to", "attachments", "attack", "civ", "defence", "family", "id",
+		"keywords", "name", "owner", "rank", "tapped", "text", "token", "zone",
+	}, methodNames(cardMethods)...)
+}
+
+func (c *card) Attr(name string) (starlark.Value, error) {
+	switch name {
+	case "attached_to":
+		return newCard(c.card.AttachedTo(), c.ctx), nil
+	case "attachments":
+		return newCards(c.card.Attachments(), c.ctx), nil
+	case "attack":
+		return starlark.MakeInt(int(c.card.GetAttack(c.ctx))), nil
+	case "civ":
+		return starlark.String(c.card.Civ()), nil
+	case "defence":
+		return starlark.MakeInt(int(c.card.GetDefence(c.ctx))), nil
+	case "family":
+		return starlark.String(c.card.Family()), nil
+	case "id":
+		return starlark.String(c.card.ID()), nil
+	case "keywords":
+		keywords := make([]starlark.Value, 0)
+		for _, k := range c.card.Keywords() {
+			keywords = append(keywords, starlark.String(k))
+		}
+		return starlark.NewList(keywords), nil
+	case "name":
+		return starlark.String(c.card.Name()), nil
+	case "owner":
+		return &player{player: c.card.Player(), ctx: c.ctx}, nil
+	case "rank":
+		return starlark.MakeInt(int(c.card.GetRank(c.ctx))), nil
+	case "tapped":
+		return starlark.Bool(c.card.Tapped), nil
+	case "text":
+		return starlark.String(c.card.Text()), nil
+	case "token":
+		return starlark.Bool(c.card.Token()), nil
+	case "zone":
+		return starlark.String(c.card.Zone()), nil
+	}
+
+	if method, ok := cardMethods[name]; ok {
+		return method.BindReceiver(c), nil
+	}
+
+	return nil, nil
+}
+
+var cardMethods = map[string]*starlark.Builtin{
+	"move":             starlark.NewBuiltin("move", cardMove),
+	"destroy":          starlark.NewBuiltin("destroy", cardDestroy),
+	"devolve":          starlark.NewBuiltin("devolve", cardDevolve),
+	"tap":              starlark.NewBuiltin("tap", cardTap),
+	"untap":            starlark.NewBuiltin("untap", cardUntap),
+	"has_family":       starlark.NewBuiltin("has_family", cardHasFamily),
+	"has_civ":          starlark.NewBuiltin("has_civ", cardHasCiv),
+	"counters":         starlark.NewBuiltin("counters", cardCounters),
+	"add_counters":     starlark.NewBuiltin("add_counters", cardAddCounters),
+	"remove_counters":  starlark.NewBuiltin("remove_counters", cardRemoveCounters),
+	"add_condition":    starlark.NewBuiltin("add_condition", cardAddCondition),
+	"remove_condition": starlark.NewBuiltin("remove_condition", cardRemoveCondition),
+}
+
+// move(zone) moves the card to the zone of its owner
+func cardMove(thread *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var zone string
+	if err := starlark.UnpackArgs(b.Name(), args, kwargs, "zone", &zone); err != nil {
+		return nil, err
+	}
+
+	container, err := toContainer(zone)
+	if err != nil {
+		return nil, err
+	}
+
+	c := b.Receiver().(*card)
+	if err := c.card.MoveCard(container); err != nil {
+		return nil, err
+	}
+
+	return starlark.None, nil
+}
+
+// destroy() destroys the card, the card running the script is the source
+func cardDestroy(thread *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	if err := starlark.UnpackArgs(b.Name(), args, kwargs); err != nil {
+		return nil, err
+	}
+
+	source, ctx, err := caller(thread, b.Name())
+	if err != nil {
+		return nil, err
+	}
+
+	ctx.Match().Destroy(b.Receiver().(*card).card, source)
+
+	return starlark.None, nil
+}
+
+// devolve() devolves the card, the card running the script is the source
+func cardDevolve(thread *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	if err := starlark.UnpackArgs(b.Name(), args, kwargs); err != nil {
+		return nil, err
+	}
+
+	source, _, err := caller(thread, b.Name())
+	if err != nil {
+		return nil, err
+	}
+
+	match.Devolve(b.Receiver().(*card).card, source)
+
+	return starlark.None, nil
+}
+
+// tap() taps the card
+func cardTap(thread *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	if err := starlark.UnpackArgs(b.Name(), args, kwargs); err != nil {
+		return nil, err
+	}
+
+	b.Receiver().(*card).card.SetTapped(true)
+
+	return starlark.None, nil
+}
+
+// untap() untaps the card
+func cardUntap(thread *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	if err := starlark.UnpackArgs(b.Name(), args, kwargs); err != nil {
+		return nil, err
+	}
+
+	b.Receiver().(*card).card.SetTapped(false)
+
+	return starlark.None, nil
+}
+
+// has_family(family) returns True if the card is of the family, once continuous effects are applied
+func cardHasFamily(thread *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var family string
+	if err := starlark.UnpackArgs(b.Name(), args, kwargs, "family", &family); err != nil {
+		return nil, err
+	}
+
+	c := b.Receiver().(*card)
+
+	return starlark.Bool(c.card.HasFamily(family, c.ctx)), nil
+}
+
+// has_civ(civ) returns True if the card is of the civilisation, once continuous effects are applied
+func cardHasCiv(thread *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var civilisation string
+	if err := starlark.UnpackArgs(b.Name(), args, kwargs, "civ", &civilisation); err != nil {
+		return nil, err
+	}
+
+	c := b.Receiver().(*card)
+
+	return starlark.Bool(c.card.HasCivilisation(civ.Civilisation(civilisation), c.ctx)), nil
+}
+
+// counters(name) returns the number of counters with the name on the card
+func cardCounters(thread *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var name string
+	if err := starlark.UnpackArgs(b.Name(), args, kwargs, "name", &name); err != nil {
+		return nil, err
+	}
+
+	return starlark.MakeInt(b.Receiver().(*card).card.Counters(name)), nil
+}
+
+// add_counters(name, n=1) puts n counters with the name on the card
+func cardAddCounters(thread *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var name string
+	n := 1
+	if err := starlark.UnpackArgs(b.Name(), args, kwargs, "name", &name, "n?", &n); err != nil {
+		return nil, err
+	}
+
+	b.Receiver().(*card).card.AddCounters(name, n)
+
+	return starlark.None, nil
+}
+
+// remove_counters(name, n=1) takes up to n counters with the name off the card
+func cardRemoveCounters(thread *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var name string
+	n := 1
+	if err := starlark.UnpackArgs(b.Name(), args, kwargs, "name", &name, "n?", &n); err != nil {
+		return nil, err
+	}
+
+	b.Receiver().(*card).card.RemoveCounters(name, n)
+
+	return starlark.None, nil
+}
+
+// add_condition(name, duration="end_of_turn") gives the card a registered condition, such as "fx.CantEvolve".
+// The duration is "end_of_turn", "your_next_turn", "permanent" or a number of turns. Only registered
+// conditions are taken, so that the card can still be stored in a snapshot
+func cardAddCondition(thread *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var name string
+	var d starlark.Value = starlark.String("end_of_turn")
+	if err := starlark.UnpackArgs(b.Name(), args, kwargs, "name", &name, "duration?", &d); err != nil {
+		return nil, err
+	}
+
+	condition, err := match.Condition(name)
+	if err != nil {
+		return nil, err
+	}
+
+	duration, err := toDuration(d)
+	if err != nil {
+		return nil, err
+	}
+
+	b.Receiver().(*card).card.AddCondition(duration, condition)
+
+	return starlark.None, nil
+}
+
+// remove_condition(name) takes a registered condition off the card
+func cardRemoveCondition(thread *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var name string
+	if err := starlark.UnpackArgs(b.Name(), args, kwargs, "name", &name); err != nil {
+		return nil, err
+	}
+
+	condition, err := match.Condition(name)
+	if err != nil {
+		return nil, err
+	}
+
+	b.Receiver().(*card).card.RemoveCondition(condition)
+
+	return starlark.None, nil
+}
+
+// player is a match.Player as seen by scripts
+type player struct {
+	player *match.Player
+	ctx    *match.Context
+}
+
+func (p *player) String() string        { return fmt.Sprintf("<player %s>", p.player.Name()) }
+func (p *player) Type() string          { return "player" }
+func (p *player) Freeze()               {}
+func (p *player) Truth() starlark.Bool  { return starlark.True }
+func (p *player) Hash() (uint32, error) { return starlark.String(p.player.Name()).Hash() }
+
+func (p *player) CompareSameType(op syntax.Token, y starlark.Value, depth int) (bool, error) {
+	return compare(op, p.player == y.(*player).player)
+}
+
+func (p *player) AttrNames() []string {
+	return append([]string{"life", "name", "opponent", "turn"}, methodNames(playerMethods)...)
+}
+
+func (p *player) Attr(name string) (starlark.Value, error) {
+	switch name {
+	case "life":
+		return starlark.MakeInt(p.player.Life()), nil
+	case "name":
+		return starlark.String(p.player.Name()), nil
+	case "opponent":
+		return &player{player: p.ctx.Match().Opponent(p.player), ctx: p.ctx}, nil
+	case "turn":
+		return starlark.Bool(p.player.IsPlayerTurn()), nil
+	}
+
+	if method, ok := playerMethods[name]; ok {
+		return method.BindReceiver(p), nil
+	}
+
+	return nil, nil
+}
+
+var playerMethods = map[string]*starlark.Builtin{
+	"cards":   starlark.NewBuiltin("cards", playerCards),
+	"search":  starlark.NewBuiltin("search", playerSearch),
+	"draw":    starlark.NewBuiltin("draw", playerDraw),
+	"shuffle": starlark.NewBuiltin("shuffle", playerShuffle),
+	"damage":  starlark.NewBuiltin("damage", playerDamage),
+	"heal":    starlark.NewBuiltin("heal", playerHeal),
+}
+
+// cards(*zones) returns the cards in the zones of the player
+func playerCards(thread *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	if len(kwargs) > 0 {
+		return nil, fmt.Errorf("%s: unexpected keyword arguments", b.Name())
+	}
+
+	containers := make([]match.Container, 0)
+
+	for _, arg := range args {
+		zone, ok := starlark.AsString(arg)
+		if !ok {
+			return nil, fmt.Errorf("%s: want zones, got %s", b.Name(), arg.Type())
+		}
+
+		container, err := toContainer(zone)
+		if err != nil {
+			return nil, err
+		}
+
+		containers = append(containers, container)
+	}
+
+	p := b.Receiver().(*player)
+
+	return newCards(p.player.CollectCards(containers...), p.ctx), nil
+}
+
+// search(cards, text, min=1, max=1, cancellable=False) asks the player to pick from the cards.
+// min and max are cut down to the number of cards, so that the player can always answer
+func playerSearch(thread *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var cards starlark.Iterable
+	var text string
+	min, max := 1, 1
+	cancellable := false
+	if err := starlark.UnpackArgs(b.Name(), args, kwargs,
+		"cards", &cards, "text", &text, "min?", &min, "max?", &max, "cancellable?", &cancellable); err != nil {
+		return nil, err
+	}
+
+	choices, err := toCards(cards)
+	if err != nil {
+		return nil, err
+	}
+
+	if min < 0 || max < 1 || min > max {
+		return nil, fmt.Errorf("%s: can't pick from %d to %d cards", b.Name(), min, max)
+	}
+
+	if min > len(choices) {
+		min = len(choices)
+	}
+
+	if max > len(choices) {
+		max = len(choices)
+	}
+
+	p := b.Receiver().(*player)
+
+	return newCards(p.player.Search(choices, text, min, max, cancellable), p.ctx), nil
+}
+
+// draw(n=1) makes the player draw n cards
+func playerDraw(thread *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	n := 1
+	if err := starlark.UnpackArgs(b.Name(), args, kwargs, "n?", &n); err != nil {
+		return nil, err
+	}
+
+	if n < 0 {
+		return nil, fmt.Errorf("%s: can't draw %d cards", b.Name(), n)
+	}
+
+	b.Receiver().(*player).player.DrawCards(n)
+
+	return starlark.None, nil
+}
+
+// shuffle() shuffles the deck of the player
+func playerShuffle(thread *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	if err := starlark.UnpackArgs(b.Name(), args, kwargs); err != nil {
+		return nil, err
+	}
+
+	b.Receiver().(*player).player.ShuffleDeck()
+
+	return starlark.None, nil
+}
+
+// damage(n) deals n damage to the player, the card running the script is the source
+func playerDamage(thread *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	n, err := unpackHealth(b, args, kwargs)
+	if err != nil {
+		return nil, err
+	}
+
+	source, ctx, err := caller(thread, b.Name())
+	if err != nil {
+		return nil, err
+	}
+
+	b.Receiver().(*player).player.Damage(source, ctx, n)
+
+	return starlark.None, nil
+}
+
+// heal(n) gives the player n life, the card running the script is the source
+func playerHeal(thread *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	n, err := unpackHealth(b, args, kwargs)
+	if err != nil {
+		return nil, err
+	}
+
+	source, ctx, err := caller(thread, b.Name())
+	if err != nil {
+		return nil, err
+	}
+
+	b.Receiver().(*player).player.Heal(source, ctx, n)
+
+	return starlark.None, nil
+}
+
+// unpackHealth returns the amount of damage or healing, from 0 to 255
+func unpackHealth(b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (uint8, error) {
+	var n int
+	if err := starlark.UnpackArgs(b.Name(), args, kwargs, "n", &n); err != nil {
+		return 0, err
+	}
+
+	if n < 0 || n > 255 {
+		return 0, fmt.Errorf("%s: n must be between 0 and 255", b.Name())
+	}
+
+	return uint8(n), nil
+}
+
+// toContainer returns the zone with the given name
+func toContainer(zone string) (match.Container, error) {
+	for _, container := range match.AllContainers() {
+		if string(container) == zone {
+			return container, nil
+		}
+	}
+
+	return "", fmt.Errorf("zone %s does not exist", zone)
+}
+
+// toDuration returns the duration with the given name, or a number of turns
+func toDuration(v starlark.Value) (match.Duration, error) {
+	switch v := v.(type) {
+	case starlark.String:
+		switch v {
+		case "end_of_turn":
+			return match.UntilEndOfTurn, nil
+		case "your_next_turn":
+			return match.UntilYourNextTurn, nil
+		case "permanent":
+			return match.Permanent, nil
+		}
+	case starlark.Int:
+		if n, ok := v.Int64(); ok && n > 0 {
+			return match.ForTurns(int(n)), nil
+		}
+	}
+
+	return match.Duration{}, fmt.Errorf("duration %s does not exist", v)
+}
+
+// compare answers == and != for values that are equal when they are the same
+func compare(op syntax.Token, same bool) (bool, error) {
+	switch op {
+	case syntax.EQL:
+		return same, nil
+	case syntax.NEQ:
+		return !same, nil
+	}
+
+	return false, fmt.Errorf("%s is not supported", op)
+}
+
+// methodNames returns the names of the methods, sorted
+func methodNames(methods map[string]*starlark.Builtin) []string {
+	names := make([]string, 0)
+
+	for name := range methods {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
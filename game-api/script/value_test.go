@@ -0,0 +1,169 @@
+package script
+
+import (
+	"encoding/json"
+	"testing"
+
+	"go.starlark.net/starlark"
+
+	"github.com/jyotiskaghosh/ganjifa/game-api/civ"
+	"github.com/jyotiskaghosh/ganjifa/game-api/fx"
+	"github.com/jyotiskaghosh/ganjifa/game-api/match"
+)
+
+// The cards can't be loaded here as they load scripts, the tests play with plain creatures
+func init() {
+	for id := 0; id < 10; id++ {
+		match.AddCard(id, func() *match.Card {
+			cb := match.CardBuilder{
+				Name:    "Creature",
+				Civ:     civ.PRITHVI,
+				Family:  "beast",
+				Attack:  1,
+				Defence: 1,
+				Effects: []match.HandlerFunc{fx.Creature},
+			}
+
+			return cb.Build()
+		})
+	}
+}
+
+// discard is the writer of a player in a test
+type discard struct{}
+
+func (discard) Write(msg interface{}) {}
+
+// newMatch returns a started match between two players who answer every prompt with the default
+func newMatch(t *testing.T) *match.Match {
+	m := match.NewWithSeed(1)
+
+	for _, name := range []string{"a", "b"} {
+		if err := m.AddPlayer(name, discard{}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	deck := make([]int, 0)
+	for i := 0; i < 40; i++ {
+		deck = append(deck, i%10)
+	}
+
+	data, _ := json.Marshal(map[string]interface{}{"header": "choose_deck", "cards": deck})
+
+	for _, p := range m.Players() {
+		p.SetDecider(match.DeciderFunc(func(p *match.Player, prompt match.Prompt) match.Decision {
+			return match.DefaultDecision(prompt)
+		}))
+		m.Input(p, data)
+	}
+
+	if !m.Started() {
+		t.Fatal("the match did not start")
+	}
+
+	return m
+}
+
+// run runs src with the first card in the deck of the current player as card
+func run(m *match.Match, src string) error {
+	deck, err := m.CurrentPlayer().Container(match.DECK)
+	if err != nil {
+		return err
+	}
+
+	ctx := match.NewContext(m, &match.EndTurnEvent{})
+
+	env := starlark.StringDict{"card": newCard(deck[0], ctx)}
+	for name, v := range builtins {
+		env[name] = v
+	}
+
+	_, err = starlark.ExecFile(newThread("test.star", deck[0], ctx), "test.star", src, env)
+
+	return err
+}
+
+func TestAddCondition(t *testing.T) {
+	m := newMatch(t)
+
+	if err := run(m, `card.add_condition("fx.CantEvolve", "permanent")`); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := m.Snapshot(); err != nil {
+		t.Fatalf("a match with a condition added by a script can't be stored: %s", err)
+	}
+
+	for _, src := range []string{
+		`card.add_condition("fx.Unknown")`,
+		`card.add_condition(lambda card, ctx: None)`,
+	} {
+		if err := run(m, src); err == nil {
+			t.Errorf("%s gave the card a condition that isn't registered", src)
+		}
+	}
+
+	if _, err := m.Snapshot(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSearchBounds(t *testing.T) {
+	m := newMatch(t)
+
+	var asked []match.Prompt
+	m.CurrentPlayer().SetDecider(match.DeciderFunc(func(p *match.Player, prompt match.Prompt) match.Decision {
+		asked = append(asked, prompt)
+		return match.DefaultDecision(prompt)
+	}))
+
+	if err := run(m, `
+def pick():
+	picked = card.owner.search(card.owner.cards("hand")[:2], "Select 5 cards", 5, 5)
+	if len(picked) != 2:
+		fail("picked %d cards" % len(picked))
+
+pick()
+`); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(asked) < 1 {
+		t.Fatal("the player was not asked")
+	}
+
+	for _, prompt := range asked {
+		if prompt.Min > len(prompt.Cards) || prompt.Max > len(prompt.Cards) {
+			t.Fatalf("the player was asked for %d to %d of %d cards", prompt.Min, prompt.Max, len(prompt.Cards))
+		}
+	}
+
+	for _, src := range []string{
+		`card.owner.search(card.owner.cards("hand"), "Select", 2, 1)`,
+		`card.owner.search(card.owner.cards("hand"), "Select", -1, 1)`,
+	} {
+		if err := run(m, src); err == nil {
+			t.Errorf("%s asked for a selection that can't be made", src)
+		}
+	}
+}
+
+func TestDraw(t *testing.T) {
+	m := newMatch(t)
+	p := m.CurrentPlayer()
+
+	hand := len(p.CollectCards(match.HAND))
+
+	if err := run(m, `card.owner.draw(-1)`); err == nil {
+		t.Error("a negative number of cards was drawn")
+	}
+
+	if err := run(m, `card.owner.draw(2)`); err != nil {
+		t.Fatal(err)
+	}
+
+	if n := len(p.CollectCards(match.HAND)); n != hand+2 {
+		t.Fatalf("the hand went from %d to %d cards after drawing 2", hand, n)
+	}
+}
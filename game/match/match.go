@@ -4,12 +4,15 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
+	"math/rand"
 	"sync"
 	"time"
 
 	"github.com/jyotiskaghosh/ganjifa/db"
 	"github.com/jyotiskaghosh/ganjifa/server"
 
+	"github.com/jyotiskaghosh/ganjifa/game-api/ai"
 	"github.com/jyotiskaghosh/ganjifa/game-api/match"
 	"go.mongodb.org/mongo-driver/bson"
 
@@ -35,6 +38,9 @@ func Get(id string) (*Match, error) {
 
 var lobbyMatches = make(chan server.MatchesListMessage)
 
+// reconnectGrace is how long a match waits for a player who lost their connection
+const reconnectGrace = 60 * time.Second
+
 // Match struct
 type Match struct {
 	id        string
@@ -42,10 +48,38 @@ type Match struct {
 	host      string
 	visible   bool
 
-	match *match.Match
-
-	created int64
-	ending  bool
+	// spectating is whether sockets that join a full match may watch it, the host can change it
+	spectating bool
+
+	// match is the game being played, it is replaced by the next one in a series
+	match      *match.Match
+	matchMutex *sync.RWMutex
+
+	bot        *ai.Bot
+	botDeck    []int
+	difficulty string
+
+	// seats holds the players by the uid of their users, so they can take their seat again after
+	// losing their connection. disconnected holds when they lost it
+	seats        map[string]*match.Player
+	disconnected map[string]time.Time
+	seatsMutex   *sync.Mutex
+
+	// sockets holds the sockets of the players by the uid of their users, they are seated again
+	// through them in every game of a series
+	sockets map[string]*server.Socket
+
+	// series is the record of the games played so far, decks holds the deck and sideboard of
+	// every player by their name and interlude is set between two games
+	series      Series
+	decks       map[string]seriesDeck
+	interlude   *interlude
+	forfeited   bool
+	seriesMutex *sync.Mutex
+
+	created  int64
+	restored int64
+	ending   bool
 }
 
 // Info struct
@@ -80,8 +114,112 @@ func Matches() []string {
 	return result
 }
 
-// New returns a new match object
-func New(matchName string, host string, visible bool) *Match {
+// Settings are the rules a match is played by, chosen by its host when creating it
+type Settings struct {
+	TimeControl match.TimeControl
+	Mulligan    match.Mulligan
+
+	// BestOf is how many games the match is played over, it must be odd. A single game if it is 0
+	BestOf int
+}
+
+// Validate returns an error if a match can't be played by the settings
+func (s Settings) Validate() error {
+	if err := s.TimeControl.Validate(); err != nil {
+		return err
+	}
+
+	if err := s.Mulligan.Validate(); err != nil {
+		return err
+	}
+
+	if s.BestOf < 0 || (s.BestOf > 0 && s.BestOf%2 == 0) {
+		return errors.New("a series must be played over an odd number of games")
+	}
+
+	return nil
+}
+
+// New returns a new match object played by the settings
+func New(matchName string, host string, visible bool, settings Settings) (*Match, error) {
+	m, err := newMatch(matchName, host, visible, settings)
+	if err != nil {
+		return nil, err
+	}
+
+	m.open()
+
+	return m, nil
+}
+
+// NewAgainstBot returns a new private match against a server side bot playing one of the standard decks.
+// The bot searches ahead when difficulty is "hard" and plays by simple rules otherwise
+func NewAgainstBot(matchName string, host string, difficulty string, settings Settings) (*Match, error) {
+	collection := db.Collection("decks")
+
+	cur, err := collection.Find(context.TODO(), bson.M{"standard": true})
+	if err != nil {
+		return nil, err
+	}
+
+	defer cur.Close(context.TODO())
+
+	decks := make([][]int, 0)
+
+	for cur.Next(context.TODO()) {
+		var deck db.Deck
+
+		if err := cur.Decode(&deck); err != nil {
+			continue
+		}
+
+		if len(deck.Cards) == 40 {
+			decks = append(decks, deck.Cards)
+		}
+	}
+
+	if len(decks) < 1 {
+		return nil, errors.New("no deck available for the bot")
+	}
+
+	m, err := newMatch(matchName, host, false, settings)
+	if err != nil {
+		return nil, err
+	}
+
+	m.bot = ai.New(m.game(), "Bot", newStrategy(difficulty))
+	m.difficulty = difficulty
+	m.botDeck = decks[rand.New(rand.NewSource(time.Now().UnixNano())).Intn(len(decks))]
+	m.decks[m.bot.Name()] = seriesDeck{Cards: m.botDeck, Sideboard: make([]int, 0)}
+
+	m.open()
+
+	go m.bot.Run()
+
+	return m, nil
+}
+
+// newMatch returns a match played by the settings, it is not listed until it is opened
+func newMatch(matchName string, host string, visible bool, settings Settings) (*Match, error) {
+	if err := settings.Validate(); err != nil {
+		return nil, err
+	}
+
+	game := match.New()
+
+	if err := game.SetTimeControl(settings.TimeControl); err != nil {
+		return nil, err
+	}
+
+	if err := game.SetMulligan(settings.Mulligan); err != nil {
+		return nil, err
+	}
+
+	bestOf := settings.BestOf
+	if bestOf == 0 {
+		bestOf = 1
+	}
+
 	id, err := shortid.Generate()
 
 	if err != nil {
@@ -89,18 +227,35 @@ func New(matchName string, host string, visible bool) *Match {
 	}
 
 	m := &Match{
-		id:        id,
-		matchName: matchName,
-		host:      host,
-		visible:   visible,
-		match:     match.New(),
+		id:         id,
+		matchName:  matchName,
+		host:       host,
+		visible:    visible,
+		match:      game,
+		matchMutex: &sync.RWMutex{},
+
+		spectating: visible,
+
+		seats:        make(map[string]*match.Player),
+		disconnected: make(map[string]time.Time),
+		seatsMutex:   &sync.Mutex{},
+		sockets:      make(map[string]*server.Socket),
+
+		series:      newSeries(bestOf),
+		decks:       make(map[string]seriesDeck),
+		seriesMutex: &sync.Mutex{},
 
 		created: time.Now().Unix(),
 	}
 
+	return m, nil
+}
+
+// open lists the match and starts its ticker
+func (m *Match) open() {
 	matchesMutex.Lock()
 
-	matches[id] = m
+	matches[m.id] = m
 
 	matchesMutex.Unlock()
 
@@ -108,9 +263,16 @@ func New(matchName string, host string, visible bool) *Match {
 
 	go m.startTicker()
 
-	logrus.Debugf("Created match %s", id)
+	logrus.Debugf("Created match %s", m.id)
+}
 
-	return m
+// newStrategy returns the strategy of a bot of the given difficulty
+func newStrategy(difficulty string) ai.Strategy {
+	if difficulty == "hard" {
+		return ai.NewMCTS(500, 2*time.Second, 12)
+	}
+
+	return ai.NewHeuristic()
 }
 
 // Name just returns "match", obligatory for a hub
@@ -136,9 +298,10 @@ func UpdateMatchList() {
 		}
 
 		matchesMessage = append(matchesMessage, server.MatchMessage{
-			ID:   match.id,
-			Host: match.host,
-			Name: match.matchName,
+			ID:         match.id,
+			Host:       match.host,
+			Name:       match.matchName,
+			Spectators: match.match.Spectators(),
 		})
 	}
 
@@ -152,8 +315,10 @@ func UpdateMatchList() {
 
 func (m *Match) startTicker() {
 	ticker := time.NewTicker(10 * time.Second) // tick every 10 seconds
+	clock := time.NewTicker(time.Second)
 
 	defer ticker.Stop()
+	defer clock.Stop()
 	defer m.Dispose()
 	defer func() {
 		if r := recover(); r != nil {
@@ -163,19 +328,46 @@ func (m *Match) startTicker() {
 
 	for {
 		select {
-		case <-m.match.Quit():
+		case <-m.game().Quit():
 			{
+				// The series goes on with a new match
+				if m.endGame() {
+					continue
+				}
+
 				logrus.Debugf("Closing match %s", m.id)
 				m.ending = true
+				m.reportSeries()
 				return
 			}
+		case <-clock.C:
+			{
+				if m.checkDisconnected() {
+					logrus.Debugf("Closing match %s", m.id)
+					m.ending = true
+					m.reportSeries()
+					return
+				}
+
+				m.game().CheckTime()
+			}
 		case <-ticker.C:
 			{
-				// Close the match if it was not started within 10 minutes of creation
-				if !m.match.Started() && m.created < time.Now().Unix()-60*10 {
+				// Close the match if it was not started within 10 minutes of creation, or of the end of the previous game
+				if !m.game().Started() && m.waitingSince() < time.Now().Unix()-60*10 {
 					logrus.Debugf("Closing match %s", m.id)
+					m.ending = true
+					m.reportSeries()
 					return
 				}
+
+				// Close a restored match if its players did not come back within 10 minutes
+				if m.restored > 0 && m.game().Vacant() && m.restored < time.Now().Unix()-60*10 {
+					logrus.Debugf("Closing match %s", m.id)
+					return
+				}
+
+				m.saveSnapshot()
 			}
 		}
 	}
@@ -195,6 +387,12 @@ func (m *Match) Dispose() {
 		s.Close()
 	}
 
+	if m.bot != nil {
+		m.bot.Stop()
+	}
+
+	m.deleteSnapshot()
+
 	matchesMutex.Lock()
 
 	delete(matches, m.id)
@@ -206,6 +404,38 @@ func (m *Match) Dispose() {
 	UpdateMatchList()
 }
 
+// saveJournal stores the journal of a started match so it can be analysed after the match is gone
+func (m *Match) saveJournal() {
+	if !m.game().Started() {
+		return
+	}
+
+	collection := db.Collection("journals")
+
+	if _, err := collection.InsertOne(context.TODO(), bson.M{
+		"match":   m.id,
+		"game":    len(m.Series().Games) + 1,
+		"journal": m.game().Journal(),
+	}); err != nil {
+		logrus.Error(err)
+	}
+}
+
+// FindJournal returns the stored journal of the given game of a finished match, games are counted from 1
+func FindJournal(id string, game int) (match.Journal, error) {
+	collection := db.Collection("journals")
+
+	var result struct {
+		Journal match.Journal `bson:"journal"`
+	}
+
+	if err := collection.FindOne(context.TODO(), bson.M{"match": id, "game": game}).Decode(&result); err != nil {
+		return match.Journal{}, err
+	}
+
+	return result.Journal, nil
+}
+
 // Find returns a match with the specified id, or an error
 func Find(id string) (*Match, error) {
 	m := matches[id]
@@ -239,10 +469,65 @@ func (m *Match) Parse(s *server.Socket, data []byte) {
 		return
 	}
 
+	if m.game().IsSpectator(s) {
+		s.Write(match.ChatMessage{
+			Header:  "warn",
+			Message: "spectators can't take part in the match",
+			Sender:  "server",
+		})
+		return
+	}
+
 	switch message.Header {
 	case "join_match":
 		{
-			if err := m.match.AddPlayer(s.User.Username, s); err != nil {
+			// Players who lost their connection between two games of a series go on with it
+			if m.rejoinInterlude(s) {
+				return
+			}
+
+			// Players who lost their connection take back their seats
+			if p := m.seat(s.User.UID); p != nil {
+				if err := m.game().Rebind(p, s); err != nil {
+					s.Write(match.ChatMessage{
+						Header:  "warn",
+						Message: err.Error(),
+						Sender:  "server",
+					})
+					return
+				}
+
+				m.seatsMutex.Lock()
+				m.sockets[s.User.UID] = s
+				delete(m.disconnected, s.User.UID)
+				m.seatsMutex.Unlock()
+
+				if opponent := m.game().Opponent(p); opponent != nil {
+					match.Warn(opponent, fmt.Sprintf("%s is back", p.Name()))
+				}
+
+				return
+			}
+
+			err := errors.New("the series is played by other players")
+			if !m.outsider(s.User.Username) {
+				err = m.game().AddPlayer(s.User.Username, s)
+			}
+
+			if err != nil {
+				if m.spectating && !m.game().IsSpectator(s) {
+					m.game().AddSpectator(s)
+
+					s.Write(match.ChatMessage{
+						Header:  "chat",
+						Message: "The match is full, you are spectating",
+						Sender:  "server",
+					})
+
+					UpdateMatchList()
+					return
+				}
+
 				s.Write(match.ChatMessage{
 					Header:  "warn",
 					Message: err.Error(),
@@ -251,6 +536,19 @@ func (m *Match) Parse(s *server.Socket, data []byte) {
 				return
 			}
 
+			if p, err := m.game().PlayerForWriter(s); err == nil {
+				m.seatsMutex.Lock()
+				m.seats[s.User.UID] = p
+				m.sockets[s.User.UID] = s
+				m.seatsMutex.Unlock()
+			}
+
+			if m.bot != nil && m.bot.Player() == nil {
+				if err := m.bot.Join(m.botDeck); err != nil {
+					logrus.Error(err)
+				}
+			}
+
 			collection := db.Collection("decks")
 
 			cur, err := collection.Find(context.TODO(), bson.M{
@@ -295,19 +593,142 @@ func (m *Match) Parse(s *server.Socket, data []byte) {
 				return
 			}
 
-			m.match.Chat(s.User.Username, msg.Message)
+			m.game().Chat(s.User.Username, msg.Message)
+		}
+	case "allow_spectators":
+		{
+			if s.User.Username != m.host {
+				return
+			}
+
+			var msg struct {
+				Allow bool `json:"allow"`
+			}
+
+			if err := json.Unmarshal(data, &msg); err != nil {
+				return
+			}
+
+			m.spectating = msg.Allow
+
+			if !m.spectating {
+				for _, w := range m.game().RemoveSpectators() {
+					if spectator, ok := w.(*server.Socket); ok {
+						spectator.Write(match.ChatMessage{
+							Header:  "warn",
+							Message: "the host turned spectating off",
+							Sender:  "server",
+						})
+						spectator.Close()
+					}
+				}
+
+				UpdateMatchList()
+			}
+		}
+	case "choose_deck":
+		{
+			m.chooseDeck(s, data)
+
+			if m.bot != nil {
+				m.bot.Poke()
+			}
+		}
+	case "choose_first":
+		{
+			m.chooseFirst(s, data)
+		}
+	case "sideboard":
+		{
+			m.sideboard(s, data)
 		}
 	default:
-		m.match.Parse(s, data)
+		m.game().Parse(s, data)
+
+		if m.bot != nil {
+			m.bot.Poke()
+		}
 	}
 }
 
-// OnSocketClose is called when a socket disconnects
+// OnSocketClose is called when a socket disconnects. A player who leaves a running match, or a series
+// between two games, has a grace period to take their seat again, during which the match is paused
 func (m *Match) OnSocketClose(s *server.Socket) {
-	if p, err := m.match.PlayerForWriter(s); err == nil {
-		match.Warn(p, "Your opponent disconnected, the match will close soon.")
-		if !m.ending {
-			m.match.End(m.match.Opponent(p), "opponent disconnected")
+	if m.game().IsSpectator(s) {
+		m.game().RemoveSpectator(s)
+		UpdateMatchList()
+		return
+	}
+
+	if m.leaveInterlude(s) {
+		return
+	}
+
+	p, err := m.game().PlayerForWriter(s)
+	if err != nil || m.ending || m.game().Ended() {
+		return
+	}
+
+	opponent := m.game().Opponent(p)
+
+	if !m.game().Started() {
+		if opponent != nil {
+			match.Warn(opponent, "Your opponent disconnected, the match will close soon.")
 		}
+
+		m.forfeited = true
+		m.game().End(opponent, "opponent disconnected")
+		return
 	}
+
+	m.game().Vacate(p)
+
+	m.seatsMutex.Lock()
+	m.disconnected[s.User.UID] = time.Now()
+	m.seatsMutex.Unlock()
+
+	match.Warn(opponent, fmt.Sprintf("Your opponent disconnected, the match is paused for up to %v", reconnectGrace))
+	m.game().BroadcastState()
+}
+
+// seat returns the player of the user with the given uid, or nil
+func (m *Match) seat(uid string) *match.Player {
+	m.seatsMutex.Lock()
+	defer m.seatsMutex.Unlock()
+
+	return m.seats[uid]
+}
+
+// checkDisconnected ends the match in favour of the opponent of a player who did not come back in time,
+// they forfeit the series along with it. It returns true if the series is over because they left between two games
+func (m *Match) checkDisconnected() bool {
+	m.seatsMutex.Lock()
+
+	var gone *match.Player
+
+	for uid, t := range m.disconnected {
+		if time.Since(t) < reconnectGrace || m.ending {
+			continue
+		}
+
+		delete(m.disconnected, uid)
+		gone = m.seats[uid]
+
+		break
+	}
+
+	m.seatsMutex.Unlock()
+
+	if gone == nil {
+		return false
+	}
+
+	if m.forfeitSeries(gone.Name()) {
+		return true
+	}
+
+	m.forfeited = true
+	m.game().End(m.game().Opponent(gone), "opponent disconnected")
+
+	return false
 }
@@ -0,0 +1,675 @@
+package match
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/jyotiskaghosh/ganjifa/db"
+	"github.com/jyotiskaghosh/ganjifa/game-api/ai"
+	"github.com/jyotiskaghosh/ganjifa/game-api/match"
+	"github.com/jyotiskaghosh/ganjifa/server"
+
+	"github.com/sirupsen/logrus"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// Series is the record of the games of a best-of-N match
+type Series struct {
+	BestOf  int            `json:"bestOf" bson:"bestOf"`
+	Players []string       `json:"players" bson:"players"`
+	Wins    map[string]int `json:"wins" bson:"wins"`
+	// Games holds the winner of every game played so far, drawn games have no winner. The series is over
+	// once a player won most of its games or all of them were played
+	Games []string `json:"games" bson:"games"`
+	// Winner is empty until the series is over, and stays empty if it ends without a winner
+	Winner string `json:"winner" bson:"winner"`
+}
+
+// SeriesMessage tells the players where the series stands
+type SeriesMessage struct {
+	Header string `json:"header"`
+	Series Series `json:"series"`
+}
+
+// SideboardMessage lists the cards a player may build their deck for the next game from
+type SideboardMessage struct {
+	Header    string `json:"header"`
+	Cards     []int  `json:"cards"`
+	Sideboard []int  `json:"sideboard"`
+}
+
+// ChooseFirstMessage asks the loser of the previous game who goes first in the next one
+type ChooseFirstMessage struct {
+	Header  string   `json:"header"`
+	Players []string `json:"players"`
+}
+
+// seriesDeck is the deck a player brought to a series, Cards is what they play the next game with
+type seriesDeck struct {
+	Cards     []int `bson:"cards"`
+	Sideboard []int `bson:"sideboard"`
+}
+
+// interlude is the time between two games of a series
+type interlude struct {
+	since int64
+	loser string
+
+	// first is the player who goes first in the next game, empty until the loser chose
+	first string
+
+	// ready holds the players who are done sideboarding
+	ready map[string]bool
+}
+
+// Series returns the record of the series
+func (m *Match) Series() Series {
+	m.seriesMutex.Lock()
+	defer m.seriesMutex.Unlock()
+
+	return m.series
+}
+
+// game returns the game being played
+func (m *Match) game() *match.Match {
+	m.matchMutex.RLock()
+	defer m.matchMutex.RUnlock()
+
+	return m.match
+}
+
+// endGame records the result of the game that just ended and sets up the next one.
+// It returns false once the series is over
+func (m *Match) endGame() bool {
+	m.saveJournal()
+
+	m.seriesMutex.Lock()
+	defer m.seriesMutex.Unlock()
+
+	if !m.game().Started() {
+		return false
+	}
+
+	winner, loser := "", ""
+
+	for _, p := range m.game().Players() {
+		if m.game().Winner(p) {
+			winner = p.Name()
+		} else {
+			loser = p.Name()
+		}
+	}
+
+	// After a draw the player who went second chooses
+	if m.game().Drawn() {
+		loser = m.game().Players()[1].Name()
+	}
+
+	if len(m.series.Players) < 1 {
+		for _, p := range m.game().Players() {
+			m.series.Players = append(m.series.Players, p.Name())
+		}
+	}
+
+	m.series.Games = append(m.series.Games, winner)
+
+	if winner != "" {
+		m.series.Wins[winner]++
+	}
+
+	if m.forfeited || (winner != "" && m.series.Wins[winner] > m.series.BestOf/2) {
+		m.series.Winner = winner
+		return false
+	}
+
+	// Drawn games count as played, the player with the most wins takes a series that ran out of games
+	if len(m.series.Games) >= m.series.BestOf {
+		m.series.Winner = m.series.leader()
+		return false
+	}
+
+	next := match.New()
+
+	if err := next.SetTimeControl(m.game().TimeControl()); err != nil {
+		logrus.Debug(err)
+	}
+
+	if err := next.SetMulligan(m.game().Mulligan()); err != nil {
+		logrus.Debug(err)
+	}
+
+	for _, w := range m.game().RemoveSpectators() {
+		next.AddSpectator(w)
+	}
+
+	m.matchMutex.Lock()
+	m.match = next
+	m.matchMutex.Unlock()
+
+	// The stored state is of the game that just ended, the next one is stored once it starts
+	m.deleteSnapshot()
+
+	m.interlude = &interlude{
+		since: time.Now().Unix(),
+		loser: loser,
+		ready: make(map[string]bool),
+	}
+
+	if m.bot != nil {
+		m.bot.Stop()
+		m.bot = ai.New(next, "Bot", newStrategy(m.difficulty))
+
+		go m.bot.Run()
+
+		m.interlude.ready[m.bot.Name()] = true
+
+		// The bot always takes the first turn when it lost
+		if loser == m.bot.Name() {
+			m.interlude.first = loser
+		}
+	}
+
+	logrus.Debugf("Match %s goes on with game %d", m.id, len(m.series.Games)+1)
+
+	for _, name := range m.series.Players {
+		m.writeInterlude(name)
+	}
+
+	return true
+}
+
+// writeInterlude sends a player what they have to do before the next game can start
+func (m *Match) writeInterlude(name string) {
+	s := m.socket(name)
+	if s == nil || m.interlude == nil {
+		return
+	}
+
+	s.Write(SeriesMessage{
+		Header: "series",
+		Series: m.series,
+	})
+
+	if !m.interlude.ready[name] {
+		deck := m.decks[name]
+
+		s.Write(SideboardMessage{
+			Header:    "sideboard",
+			Cards:     deck.Cards,
+			Sideboard: deck.Sideboard,
+		})
+	}
+
+	if name == m.interlude.loser && m.interlude.first == "" {
+		s.Write(ChooseFirstMessage{
+			Header:  "choose_first",
+			Players: m.series.Players,
+		})
+	}
+}
+
+// chooseFirst lets the loser of the previous game choose who goes first
+func (m *Match) chooseFirst(s *server.Socket, data []byte) {
+	var msg struct {
+		First string `json:"first"`
+	}
+
+	if err := json.Unmarshal(data, &msg); err != nil {
+		return
+	}
+
+	m.seriesMutex.Lock()
+
+	if m.interlude == nil || m.interlude.loser != s.User.Username {
+		m.seriesMutex.Unlock()
+		return
+	}
+
+	if !m.inSeries(msg.First) {
+		m.seriesMutex.Unlock()
+		s.Write(match.ChatMessage{
+			Header:  "warn",
+			Message: "choose one of the players to go first",
+			Sender:  "server",
+		})
+		return
+	}
+
+	m.interlude.first = msg.First
+
+	m.seriesMutex.Unlock()
+
+	m.chatPlayers(fmt.Sprintf("%s goes first in the next game", msg.First))
+
+	m.startNextGame()
+}
+
+// sideboard swaps the deck of a player for the next game, the cards must come out of their deck and sideboard
+func (m *Match) sideboard(s *server.Socket, data []byte) {
+	msg := match.CreateDeck{}
+	if err := json.Unmarshal(data, &msg); err != nil {
+		return
+	}
+
+	m.seriesMutex.Lock()
+
+	name := s.User.Username
+
+	if m.interlude == nil || m.interlude.ready[name] || !m.inSeries(name) {
+		m.seriesMutex.Unlock()
+		return
+	}
+
+	deck := m.decks[name]
+
+	pool := make(map[int]int)
+	for _, id := range append(append(make([]int, 0), deck.Cards...), deck.Sideboard...) {
+		pool[id]++
+	}
+
+	if len(msg.Cards) != len(deck.Cards) {
+		m.seriesMutex.Unlock()
+		s.Write(match.ChatMessage{
+			Header:  "warn",
+			Message: fmt.Sprintf("your deck must have %d cards", len(deck.Cards)),
+			Sender:  "server",
+		})
+		return
+	}
+
+	for _, id := range msg.Cards {
+		if pool[id] < 1 {
+			m.seriesMutex.Unlock()
+			s.Write(match.ChatMessage{
+				Header:  "warn",
+				Message: "you can only play cards from your deck and sideboard",
+				Sender:  "server",
+			})
+			return
+		}
+
+		pool[id]--
+	}
+
+	sideboard := make([]int, 0)
+	for id, n := range pool {
+		for i := 0; i < n; i++ {
+			sideboard = append(sideboard, id)
+		}
+	}
+
+	sort.Ints(sideboard)
+
+	m.decks[name] = seriesDeck{
+		Cards:     append(make([]int, 0), msg.Cards...),
+		Sideboard: sideboard,
+	}
+
+	m.interlude.ready[name] = true
+
+	m.seriesMutex.Unlock()
+
+	m.startNextGame()
+}
+
+// startNextGame seats the players in the next game once the first player is chosen and everybody is
+// done sideboarding. The player who goes first takes the first seat
+func (m *Match) startNextGame() {
+	m.seriesMutex.Lock()
+
+	i := m.interlude
+	if i == nil || i.first == "" {
+		m.seriesMutex.Unlock()
+		return
+	}
+
+	order := []string{i.first}
+	sockets := make(map[string]*server.Socket)
+
+	for _, name := range m.series.Players {
+		if name != i.first {
+			order = append(order, name)
+		}
+
+		if !i.ready[name] {
+			m.seriesMutex.Unlock()
+			return
+		}
+
+		if m.bot != nil && name == m.bot.Name() {
+			continue
+		}
+
+		if sockets[name] = m.socket(name); sockets[name] == nil {
+			m.seriesMutex.Unlock()
+			return
+		}
+	}
+
+	m.interlude = nil
+
+	decks := make(map[string][]int)
+	for _, name := range order {
+		decks[name] = m.decks[name].Cards
+	}
+
+	m.seriesMutex.Unlock()
+
+	humans := make([]*server.Socket, 0)
+
+	for _, name := range order {
+		if m.bot != nil && name == m.bot.Name() {
+			if err := m.bot.Join(decks[name]); err != nil {
+				logrus.Error(err)
+			}
+			continue
+		}
+
+		s := sockets[name]
+
+		if err := m.game().AddPlayer(name, s); err != nil {
+			logrus.Error(err)
+			continue
+		}
+
+		if p, err := m.game().PlayerForWriter(s); err == nil {
+			m.seatsMutex.Lock()
+			m.seats[s.User.UID] = p
+			m.seatsMutex.Unlock()
+		}
+
+		humans = append(humans, s)
+	}
+
+	for _, s := range humans {
+		data, err := chooseDeckMessage(decks[s.User.Username])
+		if err != nil {
+			logrus.Debug(err)
+			continue
+		}
+
+		m.game().Parse(s, data)
+	}
+
+	if m.bot != nil {
+		m.bot.Poke()
+	}
+}
+
+// rejoinInterlude takes a player who lost their connection between two games back into the series
+func (m *Match) rejoinInterlude(s *server.Socket) bool {
+	m.seriesMutex.Lock()
+
+	if m.interlude == nil || !m.inSeries(s.User.Username) {
+		m.seriesMutex.Unlock()
+		return false
+	}
+
+	m.seatsMutex.Lock()
+	m.sockets[s.User.UID] = s
+	delete(m.disconnected, s.User.UID)
+	m.seatsMutex.Unlock()
+
+	m.writeInterlude(s.User.Username)
+
+	m.seriesMutex.Unlock()
+
+	m.startNextGame()
+
+	return true
+}
+
+// inSeries returns true if the player with the given name plays the series
+func (m *Match) inSeries(name string) bool {
+	for _, player := range m.series.Players {
+		if player == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+// outsider returns true if the player with the given name can't join the next game of a series that has started
+func (m *Match) outsider(name string) bool {
+	m.seriesMutex.Lock()
+	defer m.seriesMutex.Unlock()
+
+	return len(m.series.Players) > 0 && !m.inSeries(name)
+}
+
+// opponentInSeries returns the name of the other player of the series
+func (m *Match) opponentInSeries(name string) string {
+	for _, player := range m.series.Players {
+		if player != name {
+			return player
+		}
+	}
+
+	return ""
+}
+
+// leaveInterlude starts the grace period of a player who lost their connection between two games,
+// it returns false if the series is not between two games
+func (m *Match) leaveInterlude(s *server.Socket) bool {
+	m.seriesMutex.Lock()
+	between := m.interlude != nil && m.inSeries(s.User.Username)
+	opponent := m.opponentInSeries(s.User.Username)
+	m.seriesMutex.Unlock()
+
+	if !between {
+		return false
+	}
+
+	m.seatsMutex.Lock()
+	m.disconnected[s.User.UID] = time.Now()
+	m.seatsMutex.Unlock()
+
+	if o := m.socket(opponent); o != nil {
+		o.Write(match.ChatMessage{
+			Header:  "warn",
+			Message: fmt.Sprintf("Your opponent disconnected, they have %v to come back", reconnectGrace),
+			Sender:  "server",
+		})
+	}
+
+	return true
+}
+
+// forfeitSeries ends the series in favour of the opponent of the given player if it is between two games,
+// it returns false otherwise
+func (m *Match) forfeitSeries(name string) bool {
+	m.seriesMutex.Lock()
+	defer m.seriesMutex.Unlock()
+
+	if m.interlude == nil {
+		return false
+	}
+
+	m.interlude = nil
+	m.series.Winner = m.opponentInSeries(name)
+
+	return true
+}
+
+// waitingSince returns when the match started waiting for its players to start the current game
+func (m *Match) waitingSince() int64 {
+	m.seriesMutex.Lock()
+	defer m.seriesMutex.Unlock()
+
+	if m.interlude != nil {
+		return m.interlude.since
+	}
+
+	return m.created
+}
+
+// leader returns the player with the most wins, or an empty string if no player has more than the others
+func (s Series) leader() string {
+	leader, most, tied := "", 0, false
+
+	for _, name := range s.Players {
+		switch wins := s.Wins[name]; {
+		case wins > most:
+			leader, most, tied = name, wins, false
+		case wins == most:
+			tied = true
+		}
+	}
+
+	if tied || most == 0 {
+		return ""
+	}
+
+	return leader
+}
+
+// newSeries returns the record of a series of the given length that has not started
+func newSeries(bestOf int) Series {
+	return Series{
+		BestOf:  bestOf,
+		Players: make([]string, 0),
+		Wins:    make(map[string]int),
+		Games:   make([]string, 0),
+	}
+}
+
+// chooseDeck remembers the deck a player chose for the series and hands their choice to the match.
+// A deck chosen by its uid brings its sideboard along
+func (m *Match) chooseDeck(s *server.Socket, data []byte) {
+	var msg struct {
+		UID   string `json:"uid"`
+		Cards []int  `json:"cards"`
+	}
+
+	if err := json.Unmarshal(data, &msg); err != nil {
+		return
+	}
+
+	deck := seriesDeck{Cards: msg.Cards, Sideboard: make([]int, 0)}
+
+	if msg.UID != "" {
+		var stored db.Deck
+
+		if err := db.Collection("decks").FindOne(context.TODO(), bson.M{
+			"uid": msg.UID,
+			"$or": []bson.M{
+				{"owner": s.User.UID},
+				{"standard": true},
+			},
+		}).Decode(&stored); err != nil {
+			s.Write(match.ChatMessage{
+				Header:  "warn",
+				Message: "deck not found",
+				Sender:  "server",
+			})
+			return
+		}
+
+		deck = seriesDeck{Cards: stored.Cards, Sideboard: append(make([]int, 0), stored.Sideboard...)}
+
+		d, err := chooseDeckMessage(deck.Cards)
+		if err != nil {
+			logrus.Debug(err)
+			return
+		}
+
+		data = d
+	}
+
+	if !m.game().Started() {
+		m.seriesMutex.Lock()
+		m.decks[s.User.Username] = deck
+		m.seriesMutex.Unlock()
+	}
+
+	m.game().Parse(s, data)
+}
+
+// socket returns the socket of the player with the given name, or nil if they are not connected
+func (m *Match) socket(name string) *server.Socket {
+	m.seatsMutex.Lock()
+	defer m.seatsMutex.Unlock()
+
+	for uid, s := range m.sockets {
+		if s.User.Username == name {
+			if _, ok := m.disconnected[uid]; ok {
+				return nil
+			}
+
+			return s
+		}
+	}
+
+	return nil
+}
+
+// chatPlayers sends a server chat message to the players of the series
+func (m *Match) chatPlayers(message string) {
+	for _, name := range m.Series().Players {
+		if s := m.socket(name); s != nil {
+			s.Write(match.ChatMessage{
+				Header:  "chat",
+				Message: message,
+				Sender:  "server",
+			})
+		}
+	}
+}
+
+// reportSeries stores the result of a series that is over and tells its players
+func (m *Match) reportSeries() {
+	series := m.Series()
+	if len(series.Games) < 1 {
+		return
+	}
+
+	for _, name := range series.Players {
+		if s := m.socket(name); s != nil {
+			s.Write(SeriesMessage{
+				Header: "series",
+				Series: series,
+			})
+		}
+	}
+
+	if series.BestOf > 1 && series.Winner != "" {
+		m.chatPlayers(fmt.Sprintf("%s won the series %d-%d", series.Winner,
+			series.Wins[series.Winner], series.Wins[m.opponentInSeries(series.Winner)]))
+	}
+
+	collection := db.Collection("results")
+
+	if _, err := collection.InsertOne(context.TODO(), bson.M{
+		"match":  m.id,
+		"series": series,
+	}); err != nil {
+		logrus.Error(err)
+	}
+}
+
+// FindResult returns the stored result of a finished series
+func FindResult(id string) (Series, error) {
+	collection := db.Collection("results")
+
+	var result struct {
+		Series Series `bson:"series"`
+	}
+
+	if err := collection.FindOne(context.TODO(), bson.M{"match": id}).Decode(&result); err != nil {
+		return Series{}, err
+	}
+
+	return result.Series, nil
+}
+
+// chooseDeckMessage returns the input that chooses the given deck
+func chooseDeckMessage(cards []int) ([]byte, error) {
+	return json.Marshal(struct {
+		Header string `json:"header"`
+		match.CreateDeck
+	}{"choose_deck", match.CreateDeck{Cards: cards}})
+}
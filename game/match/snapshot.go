@@ -0,0 +1,212 @@
+package match
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/jyotiskaghosh/ganjifa/db"
+	"github.com/jyotiskaghosh/ganjifa/game-api/ai"
+	"github.com/jyotiskaghosh/ganjifa/game-api/match"
+	"github.com/jyotiskaghosh/ganjifa/server"
+
+	"github.com/sirupsen/logrus"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// storedMatch is a running match as it is kept in the snapshots collection
+type storedMatch struct {
+	ID         string         `bson:"match"`
+	MatchName  string         `bson:"name"`
+	Host       string         `bson:"host"`
+	Visible    bool           `bson:"visible"`
+	Spectating bool           `bson:"spectating"`
+	Created    int64          `bson:"created"`
+	Bot        bool           `bson:"bot"`
+	Difficulty string         `bson:"difficulty"`
+	Snapshot   match.Snapshot `bson:"snapshot"`
+
+	// Seats holds the index of the player of every user by their uid
+	Seats map[string]int `bson:"seats"`
+
+	Series Series                `bson:"series"`
+	Decks  map[string]seriesDeck `bson:"decks"`
+}
+
+// saveSnapshot stores the state of a running match so it can be restored after a restart
+func (m *Match) saveSnapshot() {
+	if !m.game().Started() || m.ending {
+		return
+	}
+
+	snapshot, err := m.game().Snapshot()
+	if err != nil {
+		logrus.Debugf("Couldn't snapshot match %s: %s", m.id, err)
+		return
+	}
+
+	seats := make(map[string]int)
+
+	m.seatsMutex.Lock()
+	for uid, p := range m.seats {
+		for i, player := range m.game().Players() {
+			if p == player {
+				seats[uid] = i
+			}
+		}
+	}
+	m.seatsMutex.Unlock()
+
+	m.seriesMutex.Lock()
+	series := m.series
+	decks := make(map[string]seriesDeck)
+	for name, deck := range m.decks {
+		decks[name] = deck
+	}
+	m.seriesMutex.Unlock()
+
+	collection := db.Collection("snapshots")
+
+	if _, err := collection.ReplaceOne(
+		context.TODO(),
+		bson.M{"match": m.id},
+		storedMatch{
+			ID:         m.id,
+			MatchName:  m.matchName,
+			Host:       m.host,
+			Visible:    m.visible,
+			Spectating: m.spectating,
+			Created:    m.created,
+			Bot:        m.bot != nil,
+			Difficulty: m.difficulty,
+			Snapshot:   snapshot,
+			Seats:      seats,
+			Series:     series,
+			Decks:      decks,
+		},
+		options.Replace().SetUpsert(true),
+	); err != nil {
+		logrus.Error(err)
+	}
+}
+
+// deleteSnapshot removes the stored state of a match that is gone
+func (m *Match) deleteSnapshot() {
+	collection := db.Collection("snapshots")
+
+	if _, err := collection.DeleteOne(context.TODO(), bson.M{"match": m.id}); err != nil {
+		logrus.Error(err)
+	}
+}
+
+// SaveSnapshots stores the state of every running match, it is called when the server shuts down
+func SaveSnapshots() {
+	matchesMutex.Lock()
+
+	result := make([]*Match, 0)
+	for _, m := range matches {
+		result = append(result, m)
+	}
+
+	matchesMutex.Unlock()
+
+	for _, m := range result {
+		m.saveSnapshot()
+	}
+
+	logrus.Infof("Saved %d matches", len(result))
+}
+
+// RestoreSnapshots brings back the matches that were running when the server was stopped.
+// Their players take their seats again by joining them
+func RestoreSnapshots() {
+	collection := db.Collection("snapshots")
+
+	cur, err := collection.Find(context.TODO(), bson.M{})
+	if err != nil {
+		logrus.Error(err)
+		return
+	}
+
+	defer cur.Close(context.TODO())
+
+	for cur.Next(context.TODO()) {
+		var stored storedMatch
+
+		if err := cur.Decode(&stored); err != nil {
+			logrus.Error(err)
+			continue
+		}
+
+		restored, err := match.Restore(stored.Snapshot)
+		if err != nil {
+			logrus.Errorf("Couldn't restore match %s: %s", stored.ID, err)
+			continue
+		}
+
+		m := &Match{
+			id:         stored.ID,
+			matchName:  stored.MatchName,
+			host:       stored.Host,
+			visible:    stored.Visible,
+			spectating: stored.Spectating,
+			match:      restored,
+			matchMutex: &sync.RWMutex{},
+			difficulty: stored.Difficulty,
+
+			seats:        make(map[string]*match.Player),
+			disconnected: make(map[string]time.Time),
+			seatsMutex:   &sync.Mutex{},
+			sockets:      make(map[string]*server.Socket),
+
+			series:      newSeries(1),
+			decks:       make(map[string]seriesDeck),
+			seriesMutex: &sync.Mutex{},
+
+			created:  stored.Created,
+			restored: time.Now().Unix(),
+		}
+
+		// Matches stored before they were played as a series are a single game
+		if stored.Series.BestOf > 0 {
+			m.series = stored.Series
+
+			if m.series.Wins == nil {
+				m.series.Wins = make(map[string]int)
+			}
+		}
+
+		for name, deck := range stored.Decks {
+			m.decks[name] = deck
+		}
+
+		for uid, i := range stored.Seats {
+			if i >= 0 && i < len(restored.Players()) {
+				m.seats[uid] = restored.Players()[i]
+			}
+		}
+
+		if stored.Bot {
+			m.bot = ai.New(m.game(), "Bot", newStrategy(stored.Difficulty))
+			m.botDeck = m.decks[m.bot.Name()].Cards
+
+			if err := m.bot.Resume(); err != nil {
+				logrus.Errorf("Couldn't restore the bot of match %s: %s", stored.ID, err)
+				continue
+			}
+
+			go m.bot.Run()
+		}
+
+		matchesMutex.Lock()
+		matches[m.id] = m
+		matchesMutex.Unlock()
+
+		go m.startTicker()
+
+		logrus.Debugf("Restored match %s", m.id)
+	}
+
+	UpdateMatchList()
+}
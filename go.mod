@@ -16,5 +16,6 @@ require (
 	github.com/teris-io/shortid v0.0.0-20171029131806-771a37caa5cf // indirect
 	github.com/ventu-io/go-shortid v0.0.0-20201117134242-e59966efd125
 	go.mongodb.org/mongo-driver v1.4.6
+	go.starlark.net v0.0.0-20220328144851-d1966c6b9fcd
 	golang.org/x/crypto v0.0.0-20191205180655-e7c4368fe9dd
 )
@@ -43,9 +43,10 @@ type UserListMessage struct {
 
 // MatchMessage holds information about a match
 type MatchMessage struct {
-	ID   string `json:"id"`
-	Host string `json:"host"`
-	Name string `json:"name"`
+	ID         string `json:"id"`
+	Host       string `json:"host"`
+	Name       string `json:"name"`
+	Spectators int    `json:"spectators"`
 }
 
 // MatchesListMessage is used to list open matches